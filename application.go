@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"time"
 
 	"github.com/gookit/color"
 	"github.com/pborman/indent"
@@ -17,7 +18,6 @@ import (
 
 	"github.com/anchore/fangs"
 	"github.com/anchore/go-logger"
-	"github.com/anchore/go-logger/adapter/redact"
 )
 
 type Initializer func(*State) error
@@ -41,6 +41,22 @@ type application struct {
 	setupConfig     SetupConfig `yaml:"-" mapstructure:"-"`
 	state           State       `yaml:"-" mapstructure:"-"`
 	resourcesLoaded bool
+
+	// commandStarted is set once cobra has parsed and validated the command line, any earlier error is a usage error
+	commandStarted bool
+
+	// commandConfigs tracks the configs registered for each command (configs added via AddFlags are stored under nil)
+	commandConfigs map[*cobra.Command][]any
+
+	// configWarnings tracks problems found while loading configuration, such as deprecated configuration keys
+	configWarnings       []configWarning
+	loggedConfigWarnings []string
+
+	// configOverrides are the KEY=VALUE configuration values set with the global --set flag
+	configOverrides []string
+
+	// stdinConfig is the configuration read from stdin with `-c -`, since stdin can only be read once
+	stdinConfig map[string]any
 }
 
 var _ interface {
@@ -52,7 +68,7 @@ func New(cfg SetupConfig) Application {
 	return &application{
 		setupConfig: cfg,
 		state: State{
-			RedactStore: redact.NewStore(),
+			RedactStore: newRedactStore(),
 		},
 	}
 }
@@ -96,7 +112,22 @@ func (a *application) loadConfigs(cmd *cobra.Command, cfgs ...any) ([]any, error
 	}
 	allConfigs = append(allConfigs, cfgs...) // 3. allow for all other configs to be loaded + call PostLoad()
 
-	if err := fangs.Load(a.setupConfig.FangsConfig, cmd, allConfigs...); err != nil {
+	sources, err := a.prepareConfigSources(cmd, a.setupConfig.FangsConfig, allConfigs...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid application config: %v", err)
+	}
+
+	if err := sources.load(cmd, allConfigs...); err != nil {
+		return nil, fmt.Errorf("invalid application config: %v", err)
+	}
+
+	// register sensitive values before any configuration is logged
+	sources.postLoad(a.state.RedactStore, allConfigs...)
+
+	a.recordConfigWarnings(sources.warnings)
+	a.logConfigWarnings()
+
+	if err := validateConfigs(sources.fangsCfg.TagName, allConfigs...); err != nil {
 		return nil, fmt.Errorf("invalid application config: %v", err)
 	}
 	return allConfigs, nil
@@ -143,13 +174,18 @@ func (a *application) WrapRunE(fn func(cmd *cobra.Command, args []string) error)
 		wrapper := func(cmd *cobra.Command, args []string) (err error) {
 			defer func() {
 				// when the worker has completed (or errored) we want to exit the event loop gracefully
-				if a.state.Bus != nil {
-					a.state.Bus.Publish(ExitEvent(false))
-				}
+				a.state.Publish(ExitEvent(false))
 			}()
 			defer func() {
 				a.runPostRuns(err)
 			}()
+			defer func() {
+				// the worker runs in a separate goroutine, so a panic would not be recovered by Run
+				if v := recover(); v != nil {
+					a.dumpFlightRecorder(os.Stderr)
+					panic(v)
+				}
+			}()
 			err = fn(cmd, args)
 			return
 		}
@@ -227,6 +263,7 @@ func logConfiguration(log logger.Logger, cfgs ...any) {
 func (a *application) AddFlags(flags *pflag.FlagSet, cfgs ...any) {
 	fangs.AddFlags(a.setupConfig.FangsConfig.Logger, flags, cfgs...)
 	a.state.Config.FromCommands = append(a.state.Config.FromCommands, cfgs...)
+	a.trackCommandConfigs(nil, cfgs...)
 }
 
 func (a *application) SetupCommand(cmd *cobra.Command, cfgs ...any) *cobra.Command {
@@ -261,6 +298,13 @@ func (a *application) Run() {
 		cancel()
 	}()
 
+	defer func() {
+		if v := recover(); v != nil {
+			a.dumpFlightRecorder(os.Stderr)
+			panic(v)
+		}
+	}()
+
 	go func() {
 		select {
 		case <-signals: // first signal, cancel context
@@ -284,7 +328,11 @@ func (a *application) Run() {
 }
 
 func (a application) handleExitError(err error, stderr io.Writer) {
-	msg := color.Red.Render(strings.TrimSpace(err.Error()))
+	text := strings.TrimSpace(err.Error())
+	if a.state.RedactStore != nil {
+		text = a.state.RedactStore.RedactString(text)
+	}
+	msg := color.Red.Render(text)
 
 	hasLogger := a.state.Logger != nil
 	shouldLog := hasLogger && a.resourcesLoaded
@@ -297,6 +345,30 @@ func (a application) handleExitError(err error, stderr io.Writer) {
 	if shouldPrint {
 		fmt.Fprintln(stderr, msg)
 	}
+
+	if a.commandStarted {
+		// usage errors (such as an unknown flag) are reported before the command runs, so have nothing to record
+		a.dumpFlightRecorder(stderr)
+	}
+}
+
+// dumpFlightRecorder writes the recent log entries kept by the flight recorder to a file in the state directory and
+// reports the path of the file, so failures come with debug context regardless of the configured logging level
+func (a *application) dumpFlightRecorder(stderr io.Writer) {
+	if a.state.recorder == nil {
+		return
+	}
+
+	path, err := a.state.recorder.dump(stateDir(a.setupConfig.ID.Name), time.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "unable to write flight recorder log: %v\n", err)
+		return
+	}
+	if a.state.RunID != "" {
+		fmt.Fprintf(stderr, "recent log entries written to: %s (run ID: %s)\n", path, a.state.RunID)
+		return
+	}
+	fmt.Fprintf(stderr, "recent log entries written to: %s\n", path)
 }
 
 func (a *application) SetupRootCommand(cmd *cobra.Command, cfgs ...any) *cobra.Command {
@@ -336,6 +408,7 @@ func cp[T any](value *T) *T {
 func (a *application) setupCommand(cmd *cobra.Command, flags *pflag.FlagSet, fn *func(cmd *cobra.Command, args []string) error, cfgs ...any) *cobra.Command {
 	original := *fn
 	*fn = func(cmd *cobra.Command, args []string) error {
+		a.commandStarted = true
 		err := a.Setup(cfgs...)(cmd, args)
 		if err != nil {
 			return err
@@ -354,12 +427,20 @@ func (a *application) setupCommand(cmd *cobra.Command, flags *pflag.FlagSet, fn
 	cmd.SilenceErrors = true
 
 	a.state.Config.FromCommands = append(a.state.Config.FromCommands, cfgs...)
+	a.trackCommandConfigs(cmd, cfgs...)
 
 	fangs.AddFlags(a.setupConfig.FangsConfig.Logger, flags, cfgs...)
 
 	return cmd
 }
 
+func (a *application) trackCommandConfigs(cmd *cobra.Command, cfgs ...any) {
+	if a.commandConfigs == nil {
+		a.commandConfigs = map[*cobra.Command][]any{}
+	}
+	a.commandConfigs[cmd] = append(a.commandConfigs[cmd], cfgs...)
+}
+
 func async(cmd *cobra.Command, args []string, f func(cmd *cobra.Command, args []string) error) <-chan error {
 	errs := make(chan error)
 	go func() {
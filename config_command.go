@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -36,8 +37,9 @@ func ConfigCommand(app Application, opts *ConfigCommandConfig) *cobra.Command {
 			allConfigs := allCommandConfigs(internalApp)
 			var err error
 			if opts.LoadConfig {
-				err = loadAllConfigs(cmd, internalApp.setupConfig.FangsConfig, allConfigs)
+				err = loadAllConfigs(cmd, internalApp, internalApp.setupConfig.FangsConfig, allConfigs)
 			}
+			redactSensitiveValues(internalApp.state.RedactStore, internalApp.setupConfig.FangsConfig.TagName, allConfigs...)
 			summary := summarizeConfig(cmd, internalApp.setupConfig.FangsConfig, opts.makeFilters(internalApp.state.RedactStore), allConfigs)
 			_, writeErr := os.Stdout.WriteString(summary)
 			if writeErr != nil {
@@ -59,6 +61,41 @@ func ConfigCommand(app Application, opts *ConfigCommandConfig) *cobra.Command {
 		cmd.AddCommand(summarizeLocationsCommand(internalApp))
 	}
 
+	if opts.IncludeInitSubcommand {
+		// sub-command to write a default configuration file
+		cmd.AddCommand(configInitCommand(internalApp, opts))
+	}
+
+	if opts.IncludeGetSetSubcommands {
+		// sub-commands to show and update individual configuration values
+		cmd.AddCommand(configGetCommand(internalApp), configSetCommand(internalApp))
+	}
+
+	if opts.IncludeExplainSubcommand {
+		// sub-command to show where each configuration value was loaded from
+		cmd.AddCommand(configExplainCommand(internalApp, opts))
+	}
+
+	if opts.IncludeEnvSubcommand {
+		// sub-command to list all environment variables used for configuration
+		cmd.AddCommand(configEnvCommand(internalApp, opts))
+	}
+
+	if opts.IncludeValidateSubcommand {
+		// sub-command to validate the configuration and report any warnings
+		cmd.AddCommand(configValidateCommand(internalApp))
+	}
+
+	if opts.IncludeMigrateSubcommand {
+		// sub-command to update a configuration file to the latest configuration version
+		cmd.AddCommand(configMigrateCommand(internalApp))
+	}
+
+	if opts.IncludeEditSubcommand {
+		// sub-command to edit the configuration file in the user's editor
+		cmd.AddCommand(configEditCommand(internalApp, opts, openInEditor))
+	}
+
 	return cmd
 }
 
@@ -67,6 +104,13 @@ type valueFilterFunc func(string) string
 type ConfigCommandConfig struct {
 	LoadConfig                 bool
 	IncludeLocationsSubcommand bool
+	IncludeInitSubcommand      bool
+	IncludeGetSetSubcommands   bool
+	IncludeEditSubcommand      bool
+	IncludeExplainSubcommand   bool
+	IncludeEnvSubcommand       bool
+	IncludeValidateSubcommand  bool
+	IncludeMigrateSubcommand   bool
 	ReplaceHomeDirWithTilde    bool
 }
 
@@ -84,6 +128,55 @@ func (c *ConfigCommandConfig) WithIncludeLocationsSubcommand(include bool) *Conf
 	return c
 }
 
+// WithIncludeInitSubcommand true will include a `config init` subcommand which writes the default configuration
+// to a new configuration file
+func (c *ConfigCommandConfig) WithIncludeInitSubcommand(include bool) *ConfigCommandConfig {
+	c.IncludeInitSubcommand = include
+	return c
+}
+
+// WithIncludeGetSetSubcommands true will include `config get` and `config set` subcommands which show the resolved
+// value of a single configuration key and update a single key in the active configuration file
+func (c *ConfigCommandConfig) WithIncludeGetSetSubcommands(include bool) *ConfigCommandConfig {
+	c.IncludeGetSetSubcommands = include
+	return c
+}
+
+// WithIncludeEditSubcommand true will include a `config edit` subcommand which opens the active configuration file
+// in the user's editor, only saving the result when the configuration is valid
+func (c *ConfigCommandConfig) WithIncludeEditSubcommand(include bool) *ConfigCommandConfig {
+	c.IncludeEditSubcommand = include
+	return c
+}
+
+// WithIncludeExplainSubcommand true will include a `config explain` subcommand which shows each loaded configuration
+// value along with the source it was loaded from (flag, environment variable, profile, file, or default)
+func (c *ConfigCommandConfig) WithIncludeExplainSubcommand(include bool) *ConfigCommandConfig {
+	c.IncludeExplainSubcommand = include
+	return c
+}
+
+// WithIncludeEnvSubcommand true will include a `config env` subcommand which lists the environment variable for
+// every configuration key, along with the type, default value, description, and whether it is currently set
+func (c *ConfigCommandConfig) WithIncludeEnvSubcommand(include bool) *ConfigCommandConfig {
+	c.IncludeEnvSubcommand = include
+	return c
+}
+
+// WithIncludeValidateSubcommand true will include a `config validate` subcommand which loads the configuration and
+// reports any errors along with any warnings, such as the use of deprecated configuration keys
+func (c *ConfigCommandConfig) WithIncludeValidateSubcommand(include bool) *ConfigCommandConfig {
+	c.IncludeValidateSubcommand = include
+	return c
+}
+
+// WithIncludeMigrateSubcommand true will include a `config migrate` subcommand which updates a configuration file to
+// the latest configuration version using the migrations provided by SetupConfig.WithConfigMigrations
+func (c *ConfigCommandConfig) WithIncludeMigrateSubcommand(include bool) *ConfigCommandConfig {
+	c.IncludeMigrateSubcommand = include
+	return c
+}
+
 // WithReplaceHomeDirWithTilde adds a value filter function which replaces matching home directory values in strings
 // starting with the user's home directory to make configurations more portable. Note: this does not apply to the
 // locations subcommand, only the config command itself
@@ -97,20 +190,25 @@ func (c *ConfigCommandConfig) makeFilters(redactStore redact.Store) (filter valu
 		filter = chainFilterFuncs(redactStore.RedactString, filter)
 	}
 	if c.ReplaceHomeDirWithTilde {
-		userHome, _ := homedir.Dir()
-		if userHome != "" {
-			filter = chainFilterFuncs(filter, func(s string) string {
-				// make any defaults based on the user's home directory more portable
-				if strings.HasPrefix(s, userHome) {
-					s = strings.ReplaceAll(s, userHome, "~")
-				}
-				return s
-			})
-		}
+		filter = chainFilterFuncs(filter, homeDirFilter())
 	}
 	return filter
 }
 
+func homeDirFilter() valueFilterFunc {
+	userHome, _ := homedir.Dir()
+	if userHome == "" {
+		return nil
+	}
+	return func(s string) string {
+		// make any defaults based on the user's home directory more portable
+		if strings.HasPrefix(s, userHome) {
+			s = strings.ReplaceAll(s, userHome, "~")
+		}
+		return s
+	}
+}
+
 func chainFilterFuncs(f1, f2 valueFilterFunc) valueFilterFunc {
 	if f1 == nil {
 		return f2
@@ -136,12 +234,50 @@ func allCommandConfigs(internalApp *application) []any {
 	return append([]any{&internalApp.state.Config, internalApp}, internalApp.state.Config.FromCommands...)
 }
 
-func loadAllConfigs(cmd *cobra.Command, fangsCfg fangs.Config, allConfigs []any) error {
+// commandConfigs returns the core application configs, the configs added via AddFlags, and all configs registered for
+// the given command and its parents (found by space-separated path relative to the root command, e.g. "db update")
+func commandConfigs(internalApp *application, root *cobra.Command, commandPath string) ([]any, error) {
+	cmd, rest, err := root.Find(strings.Fields(commandPath))
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unknown command %q", commandPath)
+	}
+
+	var lineage []*cobra.Command
+	for c := cmd; c != nil; c = c.Parent() {
+		lineage = append([]*cobra.Command{c}, lineage...)
+	}
+
+	cfgs := []any{&internalApp.state.Config, internalApp}
+	cfgs = appendUnique(cfgs, internalApp.commandConfigs[nil]...)
+	for _, c := range lineage {
+		cfgs = appendUnique(cfgs, internalApp.commandConfigs[c]...)
+	}
+	return cfgs, nil
+}
+
+func appendUnique(cfgs []any, add ...any) []any {
+	for _, cfg := range add {
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+	return cfgs
+}
+
+func loadAllConfigs(cmd *cobra.Command, internalApp *application, fangsCfg fangs.Config, allConfigs []any) error {
+	sources, err := internalApp.prepareConfigSources(cmd, fangsCfg, allConfigs...)
+	if err != nil {
+		return err
+	}
+
 	var errs []error
 	for _, cfg := range allConfigs {
 		// load each config individually, as there may be conflicting names / types that will cause
 		// viper to fail to read them all and panic
-		if err := fangs.Load(fangsCfg, cmd, cfg); err != nil {
+		if err := sources.load(cmd, cfg); err != nil {
 			t := reflect.TypeOf(cfg)
 			for t.Kind() == reflect.Pointer {
 				t = t.Elem()
@@ -149,6 +285,17 @@ func loadAllConfigs(cmd *cobra.Command, fangsCfg fangs.Config, allConfigs []any)
 			errs = appendConfigLoadError(errs, t, err)
 		}
 	}
+
+	sources.postLoad(internalApp.state.RedactStore, allConfigs...)
+	internalApp.recordConfigWarnings(sources.warnings)
+
+	if len(errs) == 0 {
+		// validation rules are only checked once all configuration has loaded successfully
+		if err := validateConfigs(sources.fangsCfg.TagName, allConfigs...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -173,7 +320,7 @@ func summarizeLocationsCommand(internalApp *application) *cobra.Command {
 			if all {
 				suffix = ""
 			}
-			summary := summarizeLocations(internalApp.setupConfig.FangsConfig, suffix)
+			summary := summarizeSources(internalApp.setupConfig) + summarizeLocations(internalApp.setupConfig.FangsConfig, suffix)
 			_, err := os.Stdout.WriteString(summary)
 			return err
 		},
@@ -184,6 +331,22 @@ func summarizeLocationsCommand(internalApp *application) *cobra.Command {
 	return cmd
 }
 
+// summarizeSources lists the configuration files specified directly, which are used instead of the search locations,
+// and any dotenv files environment variables are loaded from
+func summarizeSources(cfg SetupConfig) string {
+	var out strings.Builder
+	for _, f := range fangs.Flatten(cfg.FangsConfig.Files...) {
+		if f == stdinConfigFile {
+			f = stdinSourceName
+		}
+		out.WriteString(f + "\n")
+	}
+	for _, f := range cfg.dotEnvFiles {
+		out.WriteString(f + " (dotenv)\n")
+	}
+	return out.String()
+}
+
 func summarizeLocations(fangsCfg fangs.Config, onlySuffix string) string {
 	var out strings.Builder
 	for _, f := range fangs.SummarizeLocations(fangsCfg) {
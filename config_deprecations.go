@@ -0,0 +1,148 @@
+package clio
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeprecatedKey describes a configuration key which has been renamed. Values set using the deprecated key are loaded
+// using the key which replaces it, and a warning is logged.
+type DeprecatedKey struct {
+	// Key is the deprecated configuration key, e.g. "registry.token"
+	Key string
+
+	// ReplacedBy is the configuration key to use instead, e.g. "registry.auth.token"
+	ReplacedBy string
+
+	// RemovedIn is the version which will no longer support the deprecated key (optional)
+	RemovedIn string
+}
+
+func (d DeprecatedKey) String() string {
+	msg := fmt.Sprintf("configuration key %q is deprecated, use %q instead", d.Key, d.ReplacedBy)
+	if d.RemovedIn != "" {
+		msg += fmt.Sprintf(" (support will be removed in %s)", d.RemovedIn)
+	}
+	return msg
+}
+
+// deprecationWarning returns the warning for the use of a deprecated key in a configuration source
+func deprecationWarning(key DeprecatedKey, source string) configWarning {
+	return configWarning{
+		id:      "deprecated-key:" + key.Key,
+		message: fmt.Sprintf("%s (%s)", key, source),
+	}
+}
+
+// deprecatedKeyTransform moves values using deprecated keys to the keys which replace them
+func (p *preparedConfigSources) deprecatedKeyTransform(keys []DeprecatedKey) configFileTransform {
+	return func(path string, values map[string]any) (bool, error) {
+		used := moveDeprecatedKeys(values, keys, p.fangsCfg.ProfileKey)
+		for _, key := range used {
+			p.warnings = append(p.warnings, deprecationWarning(key, "file "+path))
+		}
+		return len(used) > 0, nil
+	}
+}
+
+// aliasDeprecatedEnv loads the value of the environment variable for each deprecated key using the environment variable
+// for the replacement key, unless the replacement is also set
+func (p *preparedConfigSources) aliasDeprecatedEnv(keys []DeprecatedKey) {
+	if p.fangsCfg.AppName == "" {
+		return
+	}
+
+	for _, key := range keys {
+		deprecatedEnv := envVarName(p.fangsCfg.AppName, configKeyPath(key.Key)...)
+		value, ok := p.env[deprecatedEnv]
+		if !ok {
+			continue
+		}
+		p.warnings = append(p.warnings, deprecationWarning(key, "env "+deprecatedEnv))
+
+		env := envVarName(p.fangsCfg.AppName, configKeyPath(key.ReplacedBy)...)
+		if _, exists := p.env[env]; exists {
+			// the replacement key takes precedence
+			continue
+		}
+		p.env[env] = value
+	}
+}
+
+// moveDeprecatedKeys moves values from deprecated keys to the keys which replace them, including within each profile.
+// Values already set using the replacement key take precedence. Returns all deprecated keys found.
+func moveDeprecatedKeys(values map[string]any, keys []DeprecatedKey, profileKey string) []DeprecatedKey {
+	sections := []map[string]any{values}
+	if profileKey != "" {
+		if profiles, ok := values[strings.ToLower(profileKey)].(map[string]any); ok {
+			for _, profile := range profiles {
+				if section, ok := profile.(map[string]any); ok {
+					sections = append(sections, section)
+				}
+			}
+		}
+	}
+
+	var used []DeprecatedKey
+	for _, key := range keys {
+		found := false
+		for _, section := range sections {
+			value, ok := removeConfigKey(section, configKeyPath(key.Key)...)
+			if !ok {
+				continue
+			}
+			found = true
+			if _, exists := lookupConfigKey(section, configKeyPath(key.ReplacedBy)...); !exists {
+				setConfigKey(section, value, configKeyPath(key.ReplacedBy)...)
+			}
+		}
+		if found {
+			used = append(used, key)
+		}
+	}
+	return used
+}
+
+// configKeyPath splits the configuration key into the lowercase path used for raw configuration values
+func configKeyPath(key string) []string {
+	return strings.Split(strings.ToLower(key), ".")
+}
+
+// removeConfigKey removes and returns the raw value at the given key path
+func removeConfigKey(values map[string]any, path ...string) (any, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+	parent, ok := lookupConfigKey(values, path[:len(path)-1]...)
+	if !ok {
+		return nil, false
+	}
+	m, ok := parent.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	key := strings.ToLower(path[len(path)-1])
+	value, ok := m[key]
+	if ok {
+		delete(m, key)
+	}
+	return value, ok
+}
+
+// setConfigKey sets the raw value at the given key path, creating any missing parents
+func setConfigKey(values map[string]any, value any, path ...string) {
+	current := values
+	for i, key := range path {
+		key = strings.ToLower(key)
+		if i == len(path)-1 {
+			current[key] = value
+			return
+		}
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			current[key] = next
+		}
+		current = next
+	}
+}
@@ -0,0 +1,145 @@
+package clio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_moveDeprecatedKeys(t *testing.T) {
+	keys := []DeprecatedKey{
+		{Key: "token", ReplacedBy: "registry.auth.token"},
+		{Key: "registry.insecure", ReplacedBy: "registry.tls.skip-verify"},
+	}
+
+	tests := []struct {
+		name     string
+		values   map[string]any
+		want     map[string]any
+		wantUsed []DeprecatedKey
+	}{
+		{
+			name:   "no deprecated keys",
+			values: map[string]any{"name": "name"},
+			want:   map[string]any{"name": "name"},
+		},
+		{
+			name: "deprecated keys are moved",
+			values: map[string]any{
+				"token":    "the-token",
+				"registry": map[string]any{"insecure": true},
+			},
+			want: map[string]any{
+				"registry": map[string]any{
+					"auth": map[string]any{"token": "the-token"},
+					"tls":  map[string]any{"skip-verify": true},
+				},
+			},
+			wantUsed: keys,
+		},
+		{
+			name: "replacement key takes precedence",
+			values: map[string]any{
+				"token":    "old-token",
+				"registry": map[string]any{"auth": map[string]any{"token": "new-token"}},
+			},
+			want: map[string]any{
+				"registry": map[string]any{"auth": map[string]any{"token": "new-token"}},
+			},
+			wantUsed: keys[:1],
+		},
+		{
+			name: "profiles",
+			values: map[string]any{
+				"profiles": map[string]any{
+					"ci": map[string]any{"token": "ci-token"},
+				},
+			},
+			want: map[string]any{
+				"profiles": map[string]any{
+					"ci": map[string]any{
+						"registry": map[string]any{"auth": map[string]any{"token": "ci-token"}},
+					},
+				},
+			},
+			wantUsed: keys[:1],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used := moveDeprecatedKeys(tt.values, keys, "profiles")
+			assert.Equal(t, tt.wantUsed, used)
+			assert.Equal(t, tt.want, tt.values)
+		})
+	}
+}
+
+func newDeprecatedKeysApp(t *testing.T) (*application, string) {
+	t.Helper()
+
+	cfg := NewSetupConfig(Identification{Name: "my-app"}).WithDeprecatedKeys(
+		DeprecatedKey{Key: "registry-token", ReplacedBy: "registry.token", RemovedIn: "v2.0.0"},
+		DeprecatedKey{Key: "insecure", ReplacedBy: "registry.insecure"},
+		DeprecatedKey{Key: "old-name", ReplacedBy: "name"},
+	)
+	t.Setenv("MY_APP_INSECURE", "true")
+
+	return newConfigTestApp(t, cfg, map[string]string{
+		".my-app.yaml": "registry-token: file-token\nold-name: old-file-name\nname: file-name\n",
+	}, ".my-app.yaml")
+}
+
+func Test_DeprecatedKeys(t *testing.T) {
+	app, _ := newDeprecatedKeysApp(t)
+
+	opts := &configTestOptions{}
+	_, stderr := captureStd(func() {
+		for i := 0; i < 2; i++ {
+			_, err := app.loadConfigs(&cobra.Command{}, opts)
+			require.NoError(t, err)
+		}
+	})
+
+	assert.Equal(t, "file-token", opts.Registry.Token)
+	assert.True(t, opts.Registry.Insecure)
+	// the replacement key takes precedence over the deprecated key
+	assert.Equal(t, "file-name", opts.Name)
+
+	// warnings are only logged once per key
+	assert.Equal(t, 1, strings.Count(stderr, `configuration key "registry-token" is deprecated, use "registry.token" instead (support will be removed in v2.0.0)`), stderr)
+	assert.Equal(t, 1, strings.Count(stderr, `configuration key "insecure" is deprecated`), stderr)
+}
+
+func Test_ConfigValidateCommand_deprecatedKeys(t *testing.T) {
+	app, configFile := newDeprecatedKeysApp(t)
+	_ = app.SetupCommand(&cobra.Command{}, &configTestOptions{})
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().
+		WithIncludeValidateSubcommand(true).
+		WithIncludeExplainSubcommand(true))
+
+	validateCmd, _, err := configCmd.Find([]string{"validate"})
+	require.NoError(t, err)
+
+	stdout, stderr := captureStd(func() {
+		require.NoError(t, validateCmd.RunE(validateCmd, nil))
+	})
+
+	assert.Equal(t, "my-app configuration is valid\n", stdout)
+	assert.Contains(t, stderr, `warning: configuration key "registry-token" is deprecated, use "registry.token" instead (support will be removed in v2.0.0) (file `+configFile+`)`)
+	assert.Contains(t, stderr, `warning: configuration key "insecure" is deprecated, use "registry.insecure" instead (env MY_APP_INSECURE)`)
+
+	explainCmd, _, err := configCmd.Find([]string{"explain"})
+	require.NoError(t, err)
+
+	stdout, _ = captureStd(func() {
+		require.NoError(t, explainCmd.RunE(explainCmd, nil))
+	})
+
+	rows := explainRows(stdout)
+	assert.Equal(t, []string{"registry.token", "'file-token'", "file " + configFile + " (deprecated key registry-token)"}, rows["registry.token"])
+	assert.Equal(t, []string{"registry.insecure", "true", "env MY_APP_INSECURE (deprecated key insecure)"}, rows["registry.insecure"])
+}
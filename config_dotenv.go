@@ -0,0 +1,158 @@
+package clio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anchore/go-homedir"
+)
+
+// defaultDotEnvFile is the dotenv file loaded when no files are specified
+const defaultDotEnvFile = ".env"
+
+// dotEnvValue is a single variable read from a dotenv file
+type dotEnvValue struct {
+	value string
+	file  string
+}
+
+// readDotEnvFiles returns all <APP_NAME>_ variables from the dotenv files, variables in earlier files take precedence
+func readDotEnvFiles(appName string, files []string) (map[string]dotEnvValue, error) {
+	out := map[string]dotEnvValue{}
+	if appName == "" {
+		return out, nil
+	}
+
+	prefix := envVarName(appName, "")
+	for _, f := range files {
+		expanded, err := homedir.Expand(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to expand path: %s", f)
+		}
+
+		contents, err := os.ReadFile(expanded)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read dotenv file %s: %w", expanded, err)
+		}
+
+		values, err := parseDotEnv(contents)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dotenv file %s: %w", expanded, err)
+		}
+
+		for name, value := range values {
+			if _, exists := out[name]; exists || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			out[name] = dotEnvValue{value: value, file: expanded}
+		}
+	}
+	return out, nil
+}
+
+// parseDotEnv parses NAME=VALUE lines, supporting comments, an optional `export` prefix, and quoted values. Double
+// quoted values support \n, \t, \" and \\ escapes, single quoted values are used as-is.
+func parseDotEnv(contents []byte) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimSpace(strings.TrimPrefix(text, "export "))
+
+		name, value, ok := strings.Cut(text, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected NAME=VALUE", line)
+		}
+
+		value, err := parseDotEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		values[name] = value
+	}
+	return values, scanner.Err()
+}
+
+func parseDotEnvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	quote := value[0]
+	if quote != '"' && quote != '\'' {
+		// unquoted values may be followed by a comment
+		if idx := strings.Index(value, " #"); idx >= 0 {
+			value = value[:idx]
+		}
+		return strings.TrimSpace(value), nil
+	}
+
+	var sb strings.Builder
+	for i := 1; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == quote:
+			return sb.String(), nil
+		case c == '\\' && quote == '"' && i+1 < len(value):
+			i++
+			switch value[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(value[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated quoted value")
+}
+
+// loadDotEnvFiles loads all <APP_NAME>_ variables from the dotenv files which are not already set in the environment,
+// without modifying the environment
+func (p *preparedConfigSources) loadDotEnvFiles(files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	values, err := readDotEnvFiles(p.fangsCfg.AppName, files)
+	if err != nil {
+		return err
+	}
+
+	p.dotEnv = values
+	for name, v := range values {
+		if _, ok := p.env[name]; !ok {
+			p.env[name] = v.value
+		}
+	}
+	return nil
+}
+
+// lookupEnvFunc returns the value of the environment variable and whether it is set, the same as os.LookupEnv
+type lookupEnvFunc func(name string) (string, bool)
+
+// dotEnvLookup returns a lookup of environment variables which includes the variables read from dotenv files, the
+// environment takes precedence over dotenv files
+func dotEnvLookup(dotEnv map[string]dotEnvValue) lookupEnvFunc {
+	return func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+		v, ok := dotEnv[name]
+		return v.value, ok
+	}
+}
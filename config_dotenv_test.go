@@ -0,0 +1,153 @@
+package clio
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseDotEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     map[string]string
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name: "values",
+			contents: `# a comment
+PLAIN=value
+export EXPORTED=exported
+SPACED = spaced value # trailing comment
+EMPTY=
+SINGLE='single # not a comment \n'
+DOUBLE="double\n\"quoted\""
+`,
+			want: map[string]string{
+				"PLAIN":    "value",
+				"EXPORTED": "exported",
+				"SPACED":   "spaced value",
+				"EMPTY":    "",
+				"SINGLE":   `single # not a comment \n`,
+				"DOUBLE":   "double\n\"quoted\"",
+			},
+		},
+		{
+			name:     "missing value",
+			contents: "NAME\n",
+			wantErr:  require.Error,
+		},
+		{
+			name:     "unterminated quote",
+			contents: "NAME=\"value\n",
+			wantErr:  require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := parseDotEnv([]byte(tt.contents))
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func newDotEnvApp(t *testing.T) *application {
+	t.Helper()
+
+	t.Setenv("MY_APP_NAME", "env-name")
+
+	cfg := NewSetupConfig(Identification{Name: "my-app"}).WithDotEnvFiles(".env", "local.env", "missing.env")
+	app, _ := newConfigTestApp(t, cfg, map[string]string{
+		".env":         "MY_APP_NAME=dotenv-name\nMY_APP_COUNT=3\nOTHER_VAR=ignored\n",
+		"local.env":    "MY_APP_COUNT=5\nMY_APP_TOKEN=local-token\n",
+		".my-app.yaml": "token: file-token\n",
+	}, ".my-app.yaml")
+	return app
+}
+
+func Test_DotEnvFiles(t *testing.T) {
+	app := newDotEnvApp(t)
+
+	opts := &configTestOptions{}
+	_, err := app.loadConfigs(&cobra.Command{}, opts)
+	require.NoError(t, err)
+
+	// the environment takes precedence over dotenv files, earlier dotenv files take precedence over later files, and
+	// dotenv files take precedence over configuration files
+	assert.Equal(t, "env-name", opts.Name)
+	assert.Equal(t, 3, opts.Count)
+	assert.Equal(t, "local-token", opts.Token)
+}
+
+func Test_ConfigExplainCommand_dotEnv(t *testing.T) {
+	app := newDotEnvApp(t)
+	_ = app.SetupCommand(&cobra.Command{}, &configTestOptions{})
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeExplainSubcommand(true))
+	explainCmd, _, err := configCmd.Find([]string{"explain"})
+	require.NoError(t, err)
+
+	stdout, _ := captureStd(func() {
+		require.NoError(t, explainCmd.RunE(explainCmd, nil))
+	})
+
+	rows := explainRows(stdout)
+	assert.Equal(t, []string{"name", "'env-name'", "env MY_APP_NAME"}, rows["name"])
+	assert.Equal(t, []string{"count", "3", "env MY_APP_COUNT (.env)"}, rows["count"])
+	assert.Equal(t, []string{"token", "'local-token'", "env MY_APP_TOKEN (local.env)"}, rows["token"])
+
+	locationsCmd, _, err := configCmd.Find([]string{"locations"})
+	require.NoError(t, err)
+
+	stdout, _ = captureStd(func() {
+		require.NoError(t, locationsCmd.RunE(locationsCmd, nil))
+	})
+	assert.Contains(t, stdout, ".env (dotenv)\nlocal.env (dotenv)\nmissing.env (dotenv)\n")
+}
+
+func Test_DotEnvFiles_references(t *testing.T) {
+	cfg := NewSetupConfig(Identification{Name: "my-app"}).
+		WithDotEnvFiles().
+		WithValueInterpolation().
+		WithSecretReferences()
+	app, _ := newConfigTestApp(t, cfg, map[string]string{
+		".env":         "MY_APP_REF_NAME=dotenv-name\nMY_APP_REF_TOKEN=dotenv-token\n",
+		".my-app.yaml": "name: ${MY_APP_REF_NAME}\ntoken: env:MY_APP_REF_TOKEN\n",
+	}, ".my-app.yaml")
+
+	// variables from dotenv files are referenced the same as environment variables
+	opts := &configTestOptions{}
+	_, err := app.loadConfigs(&cobra.Command{}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "dotenv-name", opts.Name)
+	assert.Equal(t, "dotenv-token", opts.Token)
+
+	_ = app.SetupCommand(&cobra.Command{}, &configTestOptions{})
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeEnvSubcommand(true))
+	envCmd, _, err := configCmd.Find([]string{"env"})
+	require.NoError(t, err)
+
+	stdout, _ := captureStd(func() {
+		require.NoError(t, envCmd.RunE(envCmd, nil))
+	})
+	rows := explainRows(stdout)
+	assert.Equal(t, []string{"MY_APP_COUNT", "count", "int", "0", "(not set)"}, rows["MY_APP_COUNT"])
+
+	// variables set in dotenv files are shown as set
+	require.NoError(t, os.WriteFile(".env", []byte("MY_APP_COUNT=3\n"), 0o600))
+	stdout, _ = captureStd(func() {
+		require.NoError(t, envCmd.RunE(envCmd, nil))
+	})
+	rows = explainRows(stdout)
+	assert.Equal(t, []string{"MY_APP_COUNT", "count", "int", "0", "set"}, rows["MY_APP_COUNT"])
+}
@@ -0,0 +1,124 @@
+package clio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// editorRunner opens the given file for editing, returning once editing is complete
+type editorRunner func(path string) error
+
+func configEditCommand(internalApp *application, opts *ConfigCommandConfig, runEditor editorRunner) *cobra.Command {
+	id := internalApp.ID()
+	return &cobra.Command{
+		Use:   "edit",
+		Short: fmt.Sprintf("edit the active %s configuration file using $VISUAL or $EDITOR", id.Name),
+		Long: fmt.Sprintf("edit the active %s configuration file using $VISUAL or $EDITOR, creating a file from the defaults if none exists. "+
+			"The configuration file is only updated once the edited configuration loads successfully.", id.Name),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			path, contents, err := editableConfigFile(cmd, internalApp, opts)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+				return fmt.Errorf("unable to create configuration directory: %w", err)
+			}
+
+			// edits are made to a copy, leaving the original file untouched until a valid configuration is saved
+			tmp, err := createTempConfigFile(path, contents)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(tmp) //nolint:errcheck // the file has been moved on success
+
+			for {
+				if err := runEditor(tmp); err != nil {
+					return fmt.Errorf("unable to edit configuration: %w", err)
+				}
+
+				err := validateConfigFile(cmd, internalApp, path, tmp)
+				if err == nil {
+					break
+				}
+
+				_, _ = fmt.Fprintf(os.Stderr, "\n%v\n\n", err)
+				if !confirm(cmd.InOrStdin(), os.Stderr, "re-open the editor to fix the configuration? [Y/n] ") {
+					return fmt.Errorf("configuration not saved, %s is unchanged", path)
+				}
+			}
+
+			if err := moveConfigFile(tmp, path); err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(os.Stderr, "saved %s configuration to %s\n", id.Name, path)
+			return err
+		},
+	}
+}
+
+// editableConfigFile returns the active configuration file and contents, or the default configuration location and
+// default configuration contents when there is no configuration file
+func editableConfigFile(cmd *cobra.Command, internalApp *application, opts *ConfigCommandConfig) (string, []byte, error) {
+	path, err := activeConfigFile(internalApp.setupConfig.FangsConfig)
+	switch {
+	case errors.Is(err, errNoConfigFile):
+		contents := defaultConfigContents(cmd, internalApp, opts, allCommandConfigs(internalApp))
+		return defaultConfigLocation(internalApp.setupConfig.FangsConfig), []byte(contents), nil
+	case err != nil:
+		return "", nil, err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to read configuration file: %w", err)
+	}
+	return path, contents, nil
+}
+
+// openInEditor opens the file with the editor configured by $VISUAL or $EDITOR, attached to the current terminal
+func openInEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// the editor may include arguments, such as "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...) //nolint:gosec // the editor is explicitly configured by the user
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// confirm prompts the user with a yes/no question, defaulting to yes
+func confirm(in io.Reader, out io.Writer, prompt string) bool {
+	_, _ = fmt.Fprint(out, prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "", "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package clio
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/fangs"
+)
+
+func newEditCommand(t *testing.T, cfg *SetupConfig, editor editorRunner) *cobra.Command {
+	t.Helper()
+
+	type options struct {
+		Name  string `mapstructure:"name"`
+		Count int    `mapstructure:"count"`
+	}
+
+	app := New(*cfg)
+	_ = app.SetupCommand(&cobra.Command{}, &options{
+		Name: "default-name",
+	})
+
+	return configEditCommand(extractInternalApp(app), DefaultConfigCommandConfig(), editor)
+}
+
+func Test_ConfigEditCommand(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".my-app.yaml")
+	original := "name: original\n"
+	require.NoError(t, os.WriteFile(configFile, []byte(original), 0o600))
+
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	})
+	cfg.FangsConfig.Files = []string{configFile}
+
+	var edits []string
+	editor := func(path string) error {
+		contents, err := os.ReadFile(path)
+		require.NoError(t, err)
+		edits = append(edits, string(contents))
+
+		// the original file is never modified while editing
+		current, err := os.ReadFile(configFile)
+		require.NoError(t, err)
+		require.Equal(t, original, string(current))
+
+		if len(edits) == 1 {
+			return os.WriteFile(path, []byte("name: edited\ndev:\n  profile: bogus\n"), 0o600)
+		}
+		return os.WriteFile(path, []byte("name: edited\ndev:\n  profile: cpu\n"), 0o600)
+	}
+
+	editCmd := newEditCommand(t, cfg, editor)
+	editCmd.SetIn(strings.NewReader("\n"))
+
+	_, stderr := captureStd(func() {
+		require.NoError(t, editCmd.RunE(editCmd, nil))
+	})
+
+	assert.Contains(t, stderr, "invalid profile")
+	assert.Contains(t, stderr, "re-open the editor")
+	require.Len(t, edits, 2)
+	assert.Equal(t, original, edits[0])
+	// the previous edits are kept when re-opening the editor
+	assert.Contains(t, edits[1], "profile: bogus")
+
+	contents, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, "name: edited\ndev:\n  profile: cpu\n", string(contents))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func Test_ConfigEditCommand_declineReopen(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".my-app.yaml")
+	original := "name: original\n"
+	require.NoError(t, os.WriteFile(configFile, []byte(original), 0o600))
+
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	})
+	cfg.FangsConfig.Files = []string{configFile}
+
+	calls := 0
+	editCmd := newEditCommand(t, cfg, func(path string) error {
+		calls++
+		return os.WriteFile(path, []byte("count: many\n"), 0o600)
+	})
+	editCmd.SetIn(strings.NewReader("n\n"))
+
+	var err error
+	_, _ = captureStd(func() {
+		err = editCmd.RunE(editCmd, nil)
+	})
+	require.ErrorContains(t, err, "configuration not saved")
+	assert.Equal(t, 1, calls)
+
+	contents, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(contents))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func Test_ConfigEditCommand_createsFromDefaults(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "my-app", "config.yaml")
+
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	})
+	cfg.FangsConfig.Finders = []fangs.Finder{
+		func(_ fangs.Config) []string {
+			return []string{configFile}
+		},
+	}
+
+	editCmd := newEditCommand(t, cfg, func(path string) error {
+		contents, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), "name: 'default-name'")
+		return nil
+	})
+
+	_, _ = captureStd(func() {
+		require.NoError(t, editCmd.RunE(editCmd, nil))
+	})
+
+	contents, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "name: 'default-name'")
+}
+
+func Test_confirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "\n", want: true},
+		{input: "y\n", want: true},
+		{input: "YES\n", want: true},
+		{input: "n\n", want: false},
+		{input: "no", want: false},
+		{input: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			assert.Equal(t, tt.want, confirm(strings.NewReader(tt.input), out, "continue? "))
+			assert.Equal(t, "continue? ", out.String())
+		})
+	}
+}
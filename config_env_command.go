@@ -0,0 +1,100 @@
+package clio
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/fangs"
+)
+
+func configEnvCommand(internalApp *application, opts *ConfigCommandConfig) *cobra.Command {
+	return &cobra.Command{
+		Use:   "env",
+		Short: fmt.Sprintf("list all environment variables %s reads configuration from", internalApp.ID().Name),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			fangsCfg := internalApp.setupConfig.FangsConfig
+			allConfigs := allCommandConfigs(internalApp)
+
+			root := cmd
+			for root.Parent() != nil {
+				root = root.Parent()
+			}
+
+			descriptions := fangs.DescriptionProviders(
+				fangs.NewFieldDescriber(allConfigs...),
+				fangs.NewStructDescriptionTagProvider(),
+				fangs.NewCommandFlagDescriptionProvider(fangsCfg.TagName, root),
+			)
+
+			dotEnv, err := readDotEnvFiles(fangsCfg.AppName, internalApp.setupConfig.dotEnvFiles)
+			if err != nil {
+				return err
+			}
+
+			redactSensitiveValues(internalApp.state.RedactStore, fangsCfg.TagName, allConfigs...)
+			summary := summarizeEnvVars(fangsCfg, descriptions, opts.makeFilters(internalApp.state.RedactStore), dotEnvLookup(dotEnv), allConfigs)
+			_, err = os.Stdout.WriteString(summary)
+			return err
+		},
+	}
+}
+
+// summarizeEnvVars lists the environment variable for every configuration key along with the type, default value,
+// description, and whether each variable is currently set, in the environment or a dotenv file. Values of set
+// variables are never shown since they may contain secrets
+func summarizeEnvVars(cfg fangs.Config, descriptions fangs.DescriptionProvider, filter valueFilterFunc, lookupEnv lookupEnvFunc, cfgs []any) string {
+	if filter == nil {
+		filter = func(s string) string { return s }
+	}
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ENV\tKEY\tTYPE\tDEFAULT\tCURRENT\tDESCRIPTION")
+
+	seen := map[string]bool{}
+	for _, c := range cfgs {
+		visitConfigFields(cfg.TagName, c, func(path []string, field reflect.StructField, v reflect.Value) {
+			env := envVarName(cfg.AppName, path...)
+			if seen[env] {
+				return
+			}
+			seen[env] = true
+
+			current := "(not set)"
+			if _, ok := lookupEnv(env); ok {
+				current = "set"
+			}
+
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				env,
+				strings.Join(path, "."),
+				displayType(field.Type),
+				displayValue(filter, v),
+				current,
+				firstLine(descriptions.GetDescription(v, field)),
+			)
+		})
+	}
+
+	_ = w.Flush()
+	return buf.String()
+}
+
+func displayType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.String()
+}
+
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return s
+}
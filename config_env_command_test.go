@@ -0,0 +1,56 @@
+package clio
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/fangs"
+)
+
+type envOptions struct {
+	Name     string   `mapstructure:"name"`
+	Password string   `mapstructure:"password"`
+	Online   *bool    `mapstructure:"online"`
+	Names    []string `mapstructure:"names"`
+}
+
+var _ fangs.FieldDescriber = (*envOptions)(nil)
+
+func (o *envOptions) DescribeFields(descriptions fangs.FieldDescriptionSet) {
+	descriptions.Add(&o.Name, "the name to use\nwith more details")
+}
+
+func Test_ConfigEnvCommand(t *testing.T) {
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	})
+
+	app := New(*cfg)
+
+	_ = app.SetupCommand(&cobra.Command{}, &envOptions{
+		Name: "default-name",
+	})
+
+	t.Setenv("MY_APP_PASSWORD", "secret")
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeEnvSubcommand(true))
+	envCmd, _, err := configCmd.Find([]string{"env"})
+	require.NoError(t, err)
+
+	stdout, _ := captureStd(func() {
+		require.NoError(t, envCmd.RunE(envCmd, nil))
+	})
+
+	rows := explainRows(stdout)
+	assert.Equal(t, []string{"ENV", "KEY", "TYPE", "DEFAULT", "CURRENT", "DESCRIPTION"}, rows["ENV"])
+	assert.Equal(t, []string{"MY_APP_NAME", "name", "string", "'default-name'", "(not set)", "the name to use"}, rows["MY_APP_NAME"])
+	assert.Equal(t, []string{"MY_APP_PASSWORD", "password", "string", "''", "set"}, rows["MY_APP_PASSWORD"])
+	// no default value
+	assert.Equal(t, []string{"MY_APP_ONLINE", "online", "bool", "(not set)"}, rows["MY_APP_ONLINE"])
+	assert.Equal(t, []string{"MY_APP_NAMES", "names", "[]string", "[]", "(not set)"}, rows["MY_APP_NAMES"])
+	assert.Contains(t, rows, "MY_APP_LOG_LEVEL")
+	assert.NotContains(t, stdout, "secret")
+}
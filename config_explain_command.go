@@ -0,0 +1,224 @@
+package clio
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/anchore/fangs"
+)
+
+func configExplainCommand(internalApp *application, opts *ConfigCommandConfig) *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain",
+		Short: fmt.Sprintf("show each %s configuration value and where it was loaded from", internalApp.ID().Name),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			fangsCfg := internalApp.setupConfig.FangsConfig
+			allConfigs := allCommandConfigs(internalApp)
+
+			if err := loadAllConfigs(cmd, internalApp, fangsCfg, allConfigs); err != nil {
+				return err
+			}
+
+			sources, err := newConfigSources(cmd, internalApp)
+			if err != nil {
+				return err
+			}
+
+			explanation := explainConfig(fangsCfg.TagName, sources, opts.makeFilters(internalApp.state.RedactStore), allConfigs)
+			_, err = os.Stdout.WriteString(explanation)
+			return err
+		},
+	}
+}
+
+// configSources captures everything configuration values may be loaded from in order to attribute each value
+type configSources struct {
+	appName        string
+	flags          map[uintptr]*pflag.Flag
+	overrides      []string
+	files          []configFileValues
+	dotEnv         map[string]dotEnvValue
+	profileKey     string
+	profiles       []string
+	deprecatedKeys []DeprecatedKey
+}
+
+type configFileValues struct {
+	path   string
+	values map[string]any
+}
+
+func newConfigSources(cmd *cobra.Command, internalApp *application) (*configSources, error) {
+	// the raw configuration files are read, without any transforms applied, so deprecated keys can be attributed
+	cfg := internalApp.setupConfig.FangsConfig
+	files, err := internalApp.readConfigSources(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dotEnv, err := readDotEnvFiles(cfg.AppName, internalApp.setupConfig.dotEnvFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configSources{
+		appName:        cfg.AppName,
+		flags:          changedFlags(cmd),
+		overrides:      configOverrideKeys(internalApp.configOverrides),
+		files:          files,
+		dotEnv:         dotEnv,
+		profileKey:     cfg.ProfileKey,
+		profiles:       fangs.Flatten(cfg.Profiles...),
+		deprecatedKeys: internalApp.setupConfig.deprecatedKeys,
+	}, nil
+}
+
+// source describes where the value at the given key path was loaded from, checked in the same precedence order used
+// when loading configuration: flags, --set overrides, environment variables (including dotenv files), profiles,
+// configuration files, then defaults
+func (s configSources) source(path []string, v reflect.Value) string {
+	if v.CanAddr() {
+		if f, ok := s.flags[v.Addr().Pointer()]; ok {
+			return fmt.Sprintf("flag --%s", f.Name)
+		}
+	}
+
+	if slices.Contains(s.overrides, strings.ToLower(strings.Join(path, "."))) {
+		return "flag --" + configOverrideFlag
+	}
+
+	env := envVarName(s.appName, path...)
+	if isEnvSet(env) {
+		return "env " + env
+	}
+	if d, ok := s.dotEnv[env]; ok {
+		return fmt.Sprintf("env %s (%s)", env, d.file)
+	}
+
+	for _, key := range s.deprecatedKeysReplacing(path) {
+		if env := envVarName(s.appName, configKeyPath(key.Key)...); isEnvSet(env) {
+			return fmt.Sprintf("env %s (deprecated key %s)", env, key.Key)
+		}
+	}
+
+	if s.profileKey != "" {
+		// later profiles take precedence over earlier profiles
+		for i := len(s.profiles) - 1; i >= 0; i-- {
+			for _, f := range s.files {
+				if note, ok := s.lookupKey(f.values, []string{s.profileKey, s.profiles[i]}, path); ok {
+					return fmt.Sprintf("profile %s (%s)%s", s.profiles[i], f.path, note)
+				}
+			}
+		}
+	}
+
+	for _, f := range s.files {
+		if note, ok := s.lookupKey(f.values, nil, path); ok {
+			return "file " + f.path + note
+		}
+	}
+
+	return "default"
+}
+
+// lookupKey returns true if the key path is set within the section of the raw configuration values, including by a
+// deprecated key, in which case a note about the deprecated key is returned
+func (s configSources) lookupKey(values map[string]any, section []string, path []string) (string, bool) {
+	if _, ok := lookupConfigKey(values, append(slices.Clone(section), path...)...); ok {
+		return "", true
+	}
+	for _, key := range s.deprecatedKeysReplacing(path) {
+		if _, ok := lookupConfigKey(values, append(slices.Clone(section), configKeyPath(key.Key)...)...); ok {
+			return fmt.Sprintf(" (deprecated key %s)", key.Key), true
+		}
+	}
+	return "", false
+}
+
+func (s configSources) deprecatedKeysReplacing(path []string) []DeprecatedKey {
+	var keys []DeprecatedKey
+	for _, key := range s.deprecatedKeys {
+		if strings.EqualFold(key.ReplacedBy, strings.Join(path, ".")) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func explainConfig(tagName string, sources *configSources, filter valueFilterFunc, cfgs []any) string {
+	if filter == nil {
+		filter = func(s string) string { return s }
+	}
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+
+	seen := map[string]bool{}
+	for _, cfg := range cfgs {
+		visitConfigFields(tagName, cfg, func(path []string, _ reflect.StructField, v reflect.Value) {
+			key := strings.Join(path, ".")
+			if seen[key] {
+				// the same key may be used by multiple commands, but is loaded from the same source
+				return
+			}
+			seen[key] = true
+
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", key, displayValue(filter, v), sources.source(path, v))
+		})
+	}
+
+	_ = w.Flush()
+	return buf.String()
+}
+
+// displayValue formats a single configuration value for tabular output
+func displayValue(filter valueFilterFunc, v reflect.Value) string {
+	v = derefValue(v)
+	switch {
+	case !v.IsValid(), (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil():
+		return ""
+	case v.Kind() == reflect.String:
+		return fmt.Sprintf("'%s'", filter(v.String()))
+	}
+	return filter(fmt.Sprintf("%v", v.Interface()))
+}
+
+// changedFlags returns all flags which have been set, by the address of the value the flag is bound to
+func changedFlags(cmd *cobra.Command) map[uintptr]*pflag.Flag {
+	out := map[uintptr]*pflag.Flag{}
+	for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags(), cmd.InheritedFlags()} {
+		flags.VisitAll(func(f *pflag.Flag) {
+			if f.Changed {
+				out[flagValuePointer(f)] = f
+			}
+		})
+	}
+	return out
+}
+
+// flagValuePointer returns the address of the value the flag is bound to, the same way fangs matches flags to fields
+func flagValuePointer(f *pflag.Flag) uintptr {
+	v := reflect.ValueOf(f.Value)
+	if v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Struct {
+		// slice flag values, such as stringArrayValue, reference the bound value in a "value" field
+		if value := v.Elem().FieldByName("value"); value.IsValid() && value.Kind() == reflect.Pointer {
+			return value.Pointer()
+		}
+	}
+	return v.Pointer()
+}
+
+func isEnvSet(name string) bool {
+	_, ok := os.LookupEnv(name)
+	return ok
+}
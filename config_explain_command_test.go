@@ -0,0 +1,135 @@
+package clio
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/fangs"
+	"github.com/anchore/go-logger/adapter/discard"
+)
+
+func Test_ConfigExplainCommand(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), ".my-app.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`name: from-file
+enabled: true
+profiles:
+  dev:
+    password: dev-password
+`), 0o600))
+
+	t.Setenv("MY_APP_PROFILE", "dev")
+	t.Setenv("MY_APP_COUNT", "3")
+
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	})
+	require.Equal(t, []string{"dev"}, cfg.FangsConfig.Profiles)
+	cfg.FangsConfig.Files = []string{configFile}
+
+	app := New(*cfg)
+	app.(*application).State().RedactStore.Add("dev-password")
+
+	type options struct {
+		Name     string        `mapstructure:"name"`
+		Password string        `mapstructure:"password"`
+		Enabled  bool          `mapstructure:"enabled"`
+		Count    int           `mapstructure:"count"`
+		Nested   nestedOptions `mapstructure:"nested"`
+	}
+
+	_ = app.SetupCommand(&cobra.Command{}, &options{
+		Name: "default-name",
+	})
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeExplainSubcommand(true))
+	explainCmd, _, err := configCmd.Find([]string{"explain"})
+	require.NoError(t, err)
+
+	stdout, _ := captureStd(func() {
+		require.NoError(t, explainCmd.RunE(explainCmd, nil))
+	})
+
+	expected := map[string][]string{
+		"name":         {"'from-file'", "file " + configFile},
+		"password":     {"'*******'", "profile dev (" + configFile + ")"},
+		"enabled":      {"true", "file " + configFile},
+		"count":        {"3", "env MY_APP_COUNT"},
+		"nested.names": {"[]", "default"},
+		"log.level":    {"'info'", "default"},
+	}
+
+	rows := explainRows(stdout)
+	assert.Equal(t, []string{"KEY", "VALUE", "SOURCE"}, rows["KEY"])
+	for key, want := range expected {
+		assert.Equal(t, append([]string{key}, want...), rows[key], key)
+	}
+}
+
+func Test_withProfileEnvVar(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		cfg    fangs.Config
+		wantIn []string
+	}{
+		{
+			name:   "no env var",
+			cfg:    fangs.NewConfig("my-app"),
+			wantIn: nil,
+		},
+		{
+			name:   "multiple profiles",
+			env:    "dev,ci",
+			cfg:    fangs.NewConfig("my-app"),
+			wantIn: []string{"dev", "ci"},
+		},
+		{
+			name:   "profiles disabled",
+			env:    "dev",
+			cfg:    fangs.Config{AppName: "my-app"},
+			wantIn: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MY_APP_PROFILE", tt.env)
+			assert.Equal(t, tt.wantIn, withProfileEnvVar(tt.cfg).Profiles)
+		})
+	}
+}
+
+func Test_configSources_flags(t *testing.T) {
+	opts := &f1{}
+	cmd := &cobra.Command{}
+	opts.AddFlags(fangs.NewPFlagSet(discard.New(), cmd.Flags()))
+	require.NoError(t, cmd.ParseFlags([]string{"--output", "json"}))
+
+	sources := configSources{
+		appName: "my-app",
+		flags:   changedFlags(cmd),
+	}
+
+	v := reflect.ValueOf(opts).Elem()
+	assert.Equal(t, "flag --output", sources.source([]string{"output"}, v.FieldByName("Output")))
+	assert.Equal(t, "default", sources.source([]string{"extras"}, v.FieldByName("Extras")))
+}
+
+// explainRows splits the tabular explain output into columns, keyed by the first column
+func explainRows(out string) map[string][]string {
+	rows := map[string][]string{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := regexp.MustCompile(`\s{2,}`).Split(strings.TrimSpace(line), -1)
+		if fields[0] != "" {
+			rows[fields[0]] = fields
+		}
+	}
+	return rows
+}
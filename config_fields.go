@@ -0,0 +1,185 @@
+package clio
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// configFieldName returns the configuration key for the given struct field following the same rules fangs uses when
+// loading configuration, along with whether the field is squashed into the parent and whether it should be skipped
+func configFieldName(tagName string, f reflect.StructField) (name string, squash bool, skip bool) {
+	// only exported fields and non-pointer embedded structs are loaded
+	if !f.IsExported() && (!f.Anonymous || f.Type.Kind() == reflect.Pointer) {
+		return "", false, true
+	}
+
+	tag, ok := f.Tag.Lookup(tagName)
+	if !ok {
+		return f.Name, false, false
+	}
+
+	parts := strings.Split(tag, ",")
+	switch {
+	case parts[0] == "-":
+		return "", false, true
+	case slices.Contains(parts[1:], "squash"):
+		return "", true, false
+	case parts[0] == "":
+		return f.Name, false, false
+	}
+	return parts[0], false, false
+}
+
+// lookupConfigValue finds the value at the given dotted key in the first of the configs that contains the key
+func lookupConfigValue(tagName string, key string, cfgs ...any) (reflect.Value, bool) {
+	path := strings.Split(key, ".")
+	for _, cfg := range cfgs {
+		if v, ok := lookupValue(tagName, reflect.ValueOf(cfg), path); ok {
+			return v, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func lookupValue(tagName string, v reflect.Value, path []string) (reflect.Value, bool) {
+	if len(path) == 0 {
+		return v, true
+	}
+
+	v = derefValue(v)
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, squash, skip := configFieldName(tagName, t.Field(i))
+			switch {
+			case skip:
+				continue
+			case squash:
+				if found, ok := lookupValue(tagName, v.Field(i), path); ok {
+					return found, true
+				}
+			case strings.EqualFold(name, path[0]):
+				return lookupValue(tagName, v.Field(i), path[1:])
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		entry := v.MapIndex(reflect.ValueOf(path[0]).Convert(v.Type().Key()))
+		if !entry.IsValid() {
+			// the entry is not set, but it is still a valid key for the map
+			entry = reflect.Zero(v.Type().Elem())
+		}
+		return lookupValue(tagName, entry, path[1:])
+	}
+
+	return reflect.Value{}, false
+}
+
+// derefValue follows pointers and interfaces to the underlying value, using an empty value for nil struct pointers
+func derefValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			if v.Kind() == reflect.Pointer && v.Type().Elem().Kind() == reflect.Struct {
+				return reflect.New(v.Type().Elem()).Elem()
+			}
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// decodeConfigValue converts the raw value to the given type the same way values are decoded when loading configuration
+func decodeConfigValue(raw any, t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t)
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           out.Interface(),
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if err := dec.Decode(raw); err != nil {
+		return reflect.Value{}, fmt.Errorf("invalid value for type %s: %w", t, err)
+	}
+	return out.Elem(), nil
+}
+
+// formatConfigValue renders a single configuration value, using yaml for any structured values
+func formatConfigValue(v reflect.Value) (string, error) {
+	v = derefValue(v)
+	if !v.IsValid() || ((v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil()) {
+		return "", nil
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		out, err := yaml.Marshal(v.Interface())
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	return fmt.Sprintf("%v", v.Interface()), nil
+}
+
+// visitConfigFields calls the visitor with the key path and value of each configuration field that would be loaded,
+// descending into nested structs the same way fangs does
+func visitConfigFields(tagName string, cfg any, visit func(path []string, field reflect.StructField, v reflect.Value)) {
+	visitStructFields(tagName, reflect.ValueOf(cfg), nil, nil, visit)
+}
+
+func visitStructFields(tagName string, v reflect.Value, path []string, parents []reflect.Type, visit func(path []string, field reflect.StructField, v reflect.Value)) {
+	v = derefValue(v)
+	if v.Kind() != reflect.Struct || slices.Contains(parents, v.Type()) {
+		// don't keep descending into recursive types
+		return
+	}
+	parents = append(parents, v.Type())
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, squash, skip := configFieldName(tagName, f)
+		if skip {
+			continue
+		}
+
+		fieldPath := slices.Clone(path)
+		if !squash {
+			fieldPath = append(fieldPath, name)
+		}
+
+		fv := v.Field(i)
+		if derefValue(fv).Kind() == reflect.Struct {
+			visitStructFields(tagName, fv, fieldPath, parents, visit)
+			continue
+		}
+
+		visit(fieldPath, f, fv)
+	}
+}
+
+var envVarPattern = regexp.MustCompile("[^a-zA-Z0-9_]")
+
+// envVarName returns the environment variable name used for the configuration key, the same as fangs
+func envVarName(appName string, path ...string) string {
+	name := strings.Join(path, "_")
+	if appName != "" {
+		name = appName + "_" + name
+	}
+	return strings.ToUpper(envVarPattern.ReplaceAllString(name, "_"))
+}
@@ -0,0 +1,325 @@
+package clio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+
+	"github.com/anchore/fangs"
+	"github.com/anchore/go-homedir"
+)
+
+var errNoConfigFile = errors.New("no configuration file found")
+
+// includeKeys are the configuration keys which reference other configuration files to load
+var includeKeys = []string{"include", "extends"}
+
+// findConfigFiles returns the configuration files that would be loaded, in precedence order: either the explicitly
+// configured files or the files found in the search locations
+func findConfigFiles(cfg fangs.Config) ([]string, error) {
+	var files []string
+	for _, f := range fangs.Flatten(cfg.Files...) {
+		if f == stdinConfigFile {
+			return nil, errStdinConfigFile
+		}
+		expanded, err := expandConfigFile(f)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, expanded)
+	}
+
+	if len(files) > 1 && !cfg.MultiFile {
+		return nil, fmt.Errorf("multiple configuration files not allowed; got: %v", files)
+	}
+
+	// only include files in search locations if files were not specified directly
+	if len(files) > 0 {
+		return files, nil
+	}
+
+	for _, finder := range cfg.Finders {
+		for _, f := range finder(cfg) {
+			if !fileExists(f) {
+				continue
+			}
+			files = append(files, f)
+			if !cfg.MultiFile {
+				return files, nil
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// expandConfigFile expands the explicitly configured file path, verifying the file exists
+func expandConfigFile(f string) (string, error) {
+	expanded, err := homedir.Expand(f)
+	if err != nil {
+		return "", fmt.Errorf("unable to expand path: %s", f)
+	}
+	if !fileExists(expanded) {
+		return "", fmt.Errorf("file does not exist: %v", expanded)
+	}
+	return expanded, nil
+}
+
+// activeConfigFile returns the configuration file with the highest precedence
+func activeConfigFile(cfg fangs.Config) (string, error) {
+	files, err := findConfigFiles(cfg)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", errNoConfigFile
+	}
+	return files[0], nil
+}
+
+// withConfigFileReplaced returns a copy of the config which loads the replacement file in place of the original. When
+// the original is not one of the files that would be loaded, such as a file given explicitly to a command, only the
+// replacement file is loaded so it is still validated. Profiles are not applied in this case, since they may be
+// defined in other files.
+func withConfigFileReplaced(cfg fangs.Config, original, replacement string) (fangs.Config, error) {
+	files, err := findConfigFiles(cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	idx := slices.IndexFunc(files, func(f string) bool { return sameFile(f, original) })
+	if idx < 0 {
+		cfg.Files = []string{replacement}
+		cfg.Profiles = nil
+		return cfg, nil
+	}
+
+	cfg.Files = slices.Clone(files)
+	cfg.Files[idx] = replacement
+	return cfg, nil
+}
+
+// resolveConfigIncludes returns a copy of the config which loads all configuration files referenced by include or
+// extends directives. Included files are loaded immediately after the including file, so values in the including file
+// take precedence over the included files
+func resolveConfigIncludes(cfg fangs.Config) (fangs.Config, error) {
+	files, err := findConfigFiles(cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	var resolved []string
+	for _, f := range files {
+		resolved, err = appendConfigIncludes(resolved, nil, f)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if slices.Equal(files, resolved) {
+		return cfg, nil
+	}
+
+	cfg.Files = resolved
+	cfg.MultiFile = true
+	return cfg, nil
+}
+
+// readConfigSources reads the raw values of all configuration files that would be loaded, in precedence order, with
+// each file followed by the files it includes. Configuration read from stdin (-c -) is loaded in place of the stdin
+// configuration file.
+func (a *application) readConfigSources(cfg fangs.Config) ([]configFileValues, error) {
+	paths, err := a.resolveConfigSources(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []configFileValues
+	for _, path := range paths {
+		if path == stdinConfigFile {
+			values, err := a.stdinConfigValues()
+			if err != nil {
+				return nil, err
+			}
+			// the configuration read from stdin is shared by every load, so is copied before any transforms are applied
+			sources = append(sources, configFileValues{path: stdinSourceName, values: cloneConfigValues(values)})
+			continue
+		}
+
+		values, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, configFileValues{path: path, values: values})
+	}
+	return sources, nil
+}
+
+// resolveConfigSources returns the configuration files that would be loaded, including all included files. The stdin
+// configuration file is retained, followed by all files included by the configuration read from stdin.
+func (a *application) resolveConfigSources(cfg fangs.Config) ([]string, error) {
+	files := fangs.Flatten(cfg.Files...)
+	if !slices.Contains(files, stdinConfigFile) {
+		resolved, err := resolveConfigIncludes(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return findConfigFiles(resolved)
+	}
+
+	if len(files) > 1 && !cfg.MultiFile {
+		return nil, fmt.Errorf("multiple configuration files not allowed; got: %v", files)
+	}
+
+	var resolved []string
+	for _, f := range files {
+		if f != stdinConfigFile {
+			expanded, err := expandConfigFile(f)
+			if err != nil {
+				return nil, err
+			}
+			if resolved, err = appendConfigIncludes(resolved, nil, expanded); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		values, err := a.stdinConfigValues()
+		if err != nil {
+			return nil, err
+		}
+		// includes have already been resolved to absolute paths when reading from stdin
+		includes, err := configIncludeValues(values, "", stdinSourceName)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved = append(resolved, stdinConfigFile)
+		for _, include := range includes {
+			if resolved, err = appendConfigIncludes(resolved, nil, include); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// appendConfigIncludes appends the file followed by all files it includes, depth first, skipping files already included
+func appendConfigIncludes(files []string, including []string, path string) ([]string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve path: %s", path)
+	}
+
+	if slices.Contains(including, abs) {
+		return nil, fmt.Errorf("configuration include cycle: %s", strings.Join(append(including, abs), " -> "))
+	}
+
+	if slices.ContainsFunc(files, func(f string) bool { return sameFile(f, abs) }) {
+		// already included by another file with higher precedence
+		return files, nil
+	}
+	files = append(files, path)
+
+	includes, err := configIncludes(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, include := range includes {
+		files, err = appendConfigIncludes(files, append(including, abs), include)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// configIncludes returns the files referenced by include directives in the configuration file, relative paths are
+// relative to the directory of the including file
+func configIncludes(path string) ([]string, error) {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return configIncludeValues(values, filepath.Dir(path), path)
+}
+
+// configIncludeValues returns the files referenced by include directives in the raw configuration values from the
+// given source, relative paths are relative to the given directory
+func configIncludeValues(values map[string]any, dir, source string) ([]string, error) {
+	var includes []string
+	for _, key := range includeKeys {
+		v, ok := lookupConfigKey(values, key)
+		if !ok {
+			continue
+		}
+
+		var paths []string
+		if err := mapstructure.Decode(v, &paths); err != nil {
+			// a single file may be specified as a string
+			var single string
+			if err := mapstructure.Decode(v, &single); err != nil {
+				return nil, fmt.Errorf("invalid %s value in %s: must be a path or list of paths", key, source)
+			}
+			paths = fangs.Flatten(single)
+		}
+
+		for _, include := range paths {
+			expanded, err := homedir.Expand(include)
+			if err != nil {
+				return nil, fmt.Errorf("unable to expand path: %s", include)
+			}
+			if !filepath.IsAbs(expanded) {
+				expanded = filepath.Join(dir, expanded)
+			}
+			if !fileExists(expanded) {
+				return nil, fmt.Errorf("included file does not exist: %v (included from %s)", expanded, source)
+			}
+			includes = append(includes, expanded)
+		}
+	}
+	return includes, nil
+}
+
+// readConfigFile reads the configuration file into a map the same way fangs does, with all keys lowercased
+func readConfigFile(path string) (map[string]any, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("unable to read configuration file %s: %w", path, err)
+	}
+	return v.AllSettings(), nil
+}
+
+// lookupConfigKey returns the raw value at the given key path
+func lookupConfigKey(values map[string]any, path ...string) (any, bool) {
+	var current any = values
+	for _, key := range path {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[strings.ToLower(key)]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func sameFile(path, abs string) bool {
+	p, err := filepath.Abs(path)
+	return err == nil && p == abs
+}
+
+func fileExists(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
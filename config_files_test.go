@@ -0,0 +1,182 @@
+package clio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/fangs"
+)
+
+func writeConfigFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	}
+}
+
+// configTestOptions is a command configuration used to test loading configuration from each source
+type configTestOptions struct {
+	Name     string            `mapstructure:"name"`
+	Count    int               `mapstructure:"count"`
+	Token    string            `mapstructure:"token"`
+	Labels   map[string]string `mapstructure:"labels"`
+	Limits   map[string]int    `mapstructure:"limits"`
+	Registry struct {
+		Token    string `mapstructure:"token"`
+		Insecure bool   `mapstructure:"insecure"`
+	} `mapstructure:"registry"`
+	Registries []struct {
+		Token string `mapstructure:"token"`
+	} `mapstructure:"registries"`
+}
+
+// newConfigTestApp writes the files to a temporary directory, which is the working directory for the rest of the test,
+// and returns an application loading the configuration files from the directory in order (or stdin). Returns the path
+// of the first configuration file.
+func newConfigTestApp(t *testing.T, cfg *SetupConfig, files map[string]string, configFiles ...string) (*application, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, files)
+	t.Chdir(dir)
+
+	cfg.FangsConfig.Files = nil
+	for _, f := range configFiles {
+		if f != stdinConfigFile {
+			f = filepath.Join(dir, f)
+		}
+		cfg.FangsConfig.Files = append(cfg.FangsConfig.Files, f)
+	}
+
+	var configFile string
+	if len(cfg.FangsConfig.Files) > 0 {
+		configFile = cfg.FangsConfig.Files[0]
+	}
+	return New(*cfg).(*application), configFile
+}
+
+func Test_resolveConfigIncludes(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		config  []string
+		want    []string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "no includes",
+			files: map[string]string{
+				"a.yaml": "name: a\n",
+				"b.yaml": "name: b\n",
+			},
+			config: []string{"a.yaml", "b.yaml"},
+			want:   []string{"a.yaml", "b.yaml"},
+		},
+		{
+			name: "include relative to the including file",
+			files: map[string]string{
+				"team/a.yaml":      "include: ../shared/base.yaml\n",
+				"shared/base.yaml": "extends: [org.yaml]\n",
+				"shared/org.yaml":  "name: org\n",
+			},
+			config: []string{"team/a.yaml"},
+			want:   []string{"team/a.yaml", "shared/base.yaml", "shared/org.yaml"},
+		},
+		{
+			name: "includes follow each file in order",
+			files: map[string]string{
+				"a.yaml":      "include: a-base.yaml\n",
+				"a-base.yaml": "name: a-base\n",
+				"b.yaml":      "include:\n  - b-base.yaml\n  - a-base.yaml\n",
+				"b-base.yaml": "name: b-base\n",
+			},
+			config: []string{"a.yaml", "b.yaml"},
+			want:   []string{"a.yaml", "a-base.yaml", "b.yaml", "b-base.yaml"},
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"a.yaml": "include: b.yaml\n",
+				"b.yaml": "include: a.yaml\n",
+			},
+			config: []string{"a.yaml"},
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "configuration include cycle")
+			},
+		},
+		{
+			name: "missing include",
+			files: map[string]string{
+				"a.yaml": "include: missing.yaml\n",
+			},
+			config: []string{"a.yaml"},
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "included file does not exist")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			dir := t.TempDir()
+			writeConfigFiles(t, dir, tt.files)
+
+			cfg := fangs.NewConfig("my-app")
+			for _, f := range tt.config {
+				cfg.Files = append(cfg.Files, filepath.Join(dir, f))
+			}
+
+			got, err := resolveConfigIncludes(cfg)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+
+			var want []string
+			for _, f := range tt.want {
+				want = append(want, filepath.Join(dir, f))
+			}
+			assert.Equal(t, want, got.Files)
+		})
+	}
+}
+
+func Test_loadConfigsWithIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"base.yaml":      "name: base\npassword: base-password\nnames: [base]\n",
+		"team.yaml":      "include: base.yaml\npassword: team-password\nnames: [team]\n",
+		"overrides.yaml": "name: overrides\n",
+	})
+
+	type options struct {
+		Name     string   `mapstructure:"name"`
+		Password string   `mapstructure:"password"`
+		Names    []string `mapstructure:"names"`
+	}
+
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	})
+	// earlier files take precedence over later files, and included files have lower precedence than the including file
+	cfg.FangsConfig.Files = []string{filepath.Join(dir, "overrides.yaml"), filepath.Join(dir, "team.yaml")}
+
+	app := New(*cfg)
+	opts := &options{}
+	_, err := app.(*application).loadConfigs(&cobra.Command{}, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "overrides", opts.Name)
+	assert.Equal(t, "team-password", opts.Password)
+	assert.Equal(t, []string{"team", "base"}, opts.Names)
+}
@@ -0,0 +1,103 @@
+package clio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/fangs"
+	"github.com/anchore/go-homedir"
+)
+
+func configInitCommand(internalApp *application, opts *ConfigCommandConfig) *cobra.Command {
+	var force bool
+	var command string
+
+	id := internalApp.ID()
+
+	cmd := &cobra.Command{
+		Use:   "init [PATH]",
+		Short: fmt.Sprintf("write the default %s configuration to a file", id.Name),
+		Long:  fmt.Sprintf("write the default %s configuration to a file, by default: %s", id.Name, defaultConfigLocation(internalApp.setupConfig.FangsConfig)),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := defaultConfigLocation(internalApp.setupConfig.FangsConfig)
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			cfgs := allCommandConfigs(internalApp)
+			if command != "" {
+				var err error
+				cfgs, err = commandConfigs(internalApp, cmd.Root(), command)
+				if err != nil {
+					return err
+				}
+			}
+
+			path, err := writeNewConfigFile(path, defaultConfigContents(cmd, internalApp, opts, cfgs), force)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(os.Stderr, "wrote %s configuration to %s\n", id.Name, path)
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&force, "force", "", force, "overwrite the configuration file if it already exists")
+	flags.StringVarP(&command, "command", "", command, "only include the configuration used by the given command (e.g. \"db update\")")
+
+	return cmd
+}
+
+// defaultConfigContents returns the configuration summary of the given configs, suitable to use as a configuration file
+func defaultConfigContents(cmd *cobra.Command, internalApp *application, opts *ConfigCommandConfig, cfgs []any) string {
+	// note: values are intentionally not redacted, since the result is meant to be used as configuration
+	var filter valueFilterFunc
+	if opts.ReplaceHomeDirWithTilde {
+		filter = homeDirFilter()
+	}
+	contents := summarizeConfig(cmd, internalApp.setupConfig.FangsConfig, filter, cfgs)
+	if migrations := internalApp.setupConfig.configMigrations; len(migrations) > 0 {
+		contents = fmt.Sprintf("%s: %d\n\n%s", configVersionKey, latestConfigVersion(migrations), contents)
+	}
+	return contents
+}
+
+// defaultConfigLocation returns the first yaml configuration file location that would be searched
+func defaultConfigLocation(fangsCfg fangs.Config) string {
+	for _, f := range fangs.SummarizeLocations(fangsCfg) {
+		if strings.HasSuffix(f, ".yaml") {
+			return f
+		}
+	}
+	return fmt.Sprintf(".%s.yaml", fangsCfg.AppName)
+}
+
+// writeNewConfigFile writes the contents to the given path, creating any missing parent directories, and returns
+// the expanded path written to
+func writeNewConfigFile(path, contents string, overwrite bool) (string, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to expand path %q: %w", path, err)
+	}
+
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return "", fmt.Errorf("configuration file already exists: %s (use --force to overwrite)", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", fmt.Errorf("unable to create configuration directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		return "", fmt.Errorf("unable to write configuration file: %w", err)
+	}
+
+	return path, nil
+}
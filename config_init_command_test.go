@@ -0,0 +1,90 @@
+package clio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConfigInitCommand(t *testing.T) {
+	type rootOptions struct {
+		Name string `mapstructure:"name"`
+	}
+
+	type subOptions struct {
+		Password string `mapstructure:"password"`
+	}
+
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	})
+
+	app := New(*cfg)
+
+	// emulate a PostLoad hook which adds values to redact
+	app.(*application).State().RedactStore.Add("default-password")
+
+	root := app.SetupRootCommand(&cobra.Command{}, &rootOptions{Name: "default-name"})
+	root.AddCommand(app.SetupCommand(&cobra.Command{Use: "sub"}, &subOptions{Password: "default-password"}))
+	root.AddCommand(app.SetupCommand(&cobra.Command{Use: "other"}))
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeInitSubcommand(true))
+	root.AddCommand(configCmd)
+
+	initCmd, _, err := configCmd.Find([]string{"init"})
+	require.NoError(t, err)
+	require.Equal(t, "init", initCmd.Name())
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "config.yaml")
+
+	_, stderr := captureStd(func() {
+		require.NoError(t, initCmd.RunE(initCmd, []string{path}))
+	})
+	require.Contains(t, stderr, path)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "# explicitly set the logging level")
+	require.Contains(t, string(contents), "name: 'default-name'")
+	// values are written as-is, since this is meant to be used as configuration
+	require.Contains(t, string(contents), "password: 'default-password'")
+
+	// refuse to overwrite an existing file
+	require.NoError(t, os.WriteFile(path, []byte("name: mine\n"), 0o600))
+	err = initCmd.RunE(initCmd, []string{path})
+	require.ErrorContains(t, err, "already exists")
+
+	contents, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "name: mine\n", string(contents))
+
+	// only include configuration for the given command
+	require.NoError(t, initCmd.Flags().Set("force", "true"))
+	require.NoError(t, initCmd.Flags().Set("command", "other"))
+	_, _ = captureStd(func() {
+		require.NoError(t, initCmd.RunE(initCmd, []string{path}))
+	})
+
+	contents, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "level: 'warn'")
+	require.Contains(t, string(contents), "name: 'default-name'")
+	require.NotContains(t, string(contents), "password")
+
+	require.NoError(t, initCmd.Flags().Set("command", "bogus"))
+	require.Error(t, initCmd.RunE(initCmd, []string{path}))
+}
+
+func Test_defaultConfigLocation(t *testing.T) {
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	})
+	require.Equal(t, ".my-app.yaml", defaultConfigLocation(cfg.FangsConfig))
+
+	cfg.FangsConfig.Finders = nil
+	require.Equal(t, ".my-app.yaml", defaultConfigLocation(cfg.FangsConfig))
+}
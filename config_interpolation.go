@@ -0,0 +1,76 @@
+package clio
+
+import (
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+// interpolationPattern matches ${NAME} and ${NAME:-default} references, along with the $${ escape sequence
+var interpolationPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// interpolationTransform replaces environment variable references in configuration values, recording each
+// interpolated value so it may be redacted if it is used within a sensitive field
+func (p *preparedConfigSources) interpolationTransform() configValueTransform {
+	lookupEnv := dotEnvLookup(p.dotEnv)
+	return func(value string) (string, bool, error) {
+		interpolated, values := interpolate(value, lookupEnv)
+		for _, v := range values {
+			if v != "" && !slices.Contains(p.interpolated, v) {
+				p.interpolated = append(p.interpolated, v)
+			}
+		}
+		return interpolated, interpolated != value, nil
+	}
+}
+
+// interpolate replaces ${NAME} with the value of the NAME environment variable (or an empty string when unset) and
+// ${NAME:-default} with the value of NAME, or the default when NAME is unset or empty. $${ is replaced with a
+// literal ${. Returns the resulting string and all values which were interpolated.
+func interpolate(value string, lookupEnv lookupEnvFunc) (string, []string) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+
+	var values []string
+	out := interpolationPattern.ReplaceAllStringFunc(value, func(match string) string {
+		if match == "$${" {
+			return "${"
+		}
+		groups := interpolationPattern.FindStringSubmatch(match)
+		v, _ := lookupEnv(groups[1])
+		if v == "" && strings.Contains(match, ":-") {
+			v = groups[2]
+		}
+		values = append(values, v)
+		return v
+	})
+	return out, values
+}
+
+// redactInterpolatedValues adds each interpolated value used within a sensitive field to the redact store, so secrets
+// interpolated into a larger value (e.g. the password in a URL) are also redacted wherever they appear alone
+func redactInterpolatedValues(store redact.Store, tagName string, interpolated []string, cfgs ...any) {
+	if store == nil || len(interpolated) == 0 {
+		return
+	}
+
+	isSensitive := sensitiveFieldMatcher(cfgs...)
+	for _, cfg := range cfgs {
+		visitConfigFields(tagName, cfg, func(_ []string, f reflect.StructField, v reflect.Value) {
+			if !isSensitive(f, v) {
+				return
+			}
+			for _, s := range stringValues(v) {
+				for _, i := range interpolated {
+					if strings.Contains(s, i) {
+						store.Add(i)
+					}
+				}
+			}
+		})
+	}
+}
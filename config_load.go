@@ -0,0 +1,413 @@
+package clio
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	"dario.cat/mergo"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/anchore/fangs"
+)
+
+// load loads the configs from the prepared sources with the same precedence and behavior as fangs.Load, with --set
+// overrides between flags and environment variables: flags, overrides, environment variables, profiles, configuration
+// files, then defaults. All values are read from the in-memory sources, so transformed values such as resolved secrets
+// are never written to disk, the environment, or flags.
+func (p *preparedConfigSources) load(cmd *cobra.Command, cfgs ...any) error {
+	for _, cfg := range cfgs {
+		if reflect.TypeOf(cfg).Kind() != reflect.Pointer {
+			return fmt.Errorf("configuration must be a pointer, got: %T", cfg)
+		}
+	}
+
+	v, err := p.newViper()
+	if err != nil {
+		return err
+	}
+
+	b := configBinder{
+		sources: p,
+		viper:   v,
+		flags:   map[uintptr]*pflag.Flag{},
+		changed: map[string]struct{}{},
+		visited: map[reflect.Value]struct{}{},
+	}
+	if cmd != nil {
+		for _, flags := range []*pflag.FlagSet{cmd.PersistentFlags(), cmd.Flags()} {
+			flags.VisitAll(func(f *pflag.Flag) {
+				b.flags[flagValuePointer(f)] = f
+			})
+		}
+	}
+
+	for _, cfg := range cfgs {
+		b.bindFields(reflect.ValueOf(cfg), nil, nil)
+
+		// environment variables apply to every key, including keys only set in configuration files
+		for _, key := range v.AllKeys() {
+			if _, ok := b.changed[key]; ok {
+				continue
+			}
+			if value, ok := p.env[envVarName(p.fangsCfg.AppName, configKeyPath(key)...)]; ok {
+				v.Set(key, value)
+			}
+		}
+
+		// overrides take precedence over environment variables, but not over flags for the key or a parent map
+		for _, key := range sortedKeys(p.overrides) {
+			if !b.changedKey(key) {
+				p.setOverride(v, key, cfgs...)
+			}
+		}
+
+		err := unmarshalRecover(v, cfg, func(dc *mapstructure.DecoderConfig) {
+			dc.TagName = p.fangsCfg.TagName
+			// use what is present in the configuration instead of merging with existing defaults
+			dc.ZeroFields = true
+		})
+		if err != nil {
+			return err
+		}
+
+		// paths are expanded before PostLoad, so PostLoad sees the paths which will be used
+		expandPathFields(p.fangsCfg.TagName, cfg)
+
+		if err := postLoad(reflect.ValueOf(cfg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newViper returns a viper instance with the values of all configuration files merged in precedence order, slices are
+// appended so entries from higher precedence files are first, then any requested profiles are merged on top
+func (p *preparedConfigSources) newViper() (*viper.Viper, error) {
+	v := viper.New()
+
+	var paths []string
+	for _, f := range p.files {
+		all := v.AllSettings()
+		if err := mergo.Merge(&all, cloneConfigValues(f.values), mergo.WithAppendSlice); err != nil {
+			return nil, err
+		}
+		if err := v.MergeConfigMap(all); err != nil {
+			return nil, err
+		}
+		paths = append(paths, f.path)
+	}
+	v.Set("config", strings.Join(paths, ","))
+
+	return v, p.mergeProfiles(v)
+}
+
+// unmarshalRecover unmarshals the values into the config, converting panics from mapstructure into errors the same way
+// fangs does. mapstructure initializes squashed pointer structs without checking they can be set, which panics for
+// embedded unexported pointer fields (e.g. `*private`), which can't be set with reflection.
+func unmarshalRecover(v *viper.Viper, cfg any, opts ...viper.DecoderConfigOption) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg := fmt.Sprintf("%v", r)
+			if strings.Contains(msg, "unexported field") {
+				err = fmt.Errorf("unsupported type for squash: embedded unexported pointer field cannot be set via reflection: %v", r)
+				return
+			}
+			panic(r)
+		}
+	}()
+	return v.Unmarshal(cfg, opts...)
+}
+
+// mergeProfiles merges the values of each requested profile over the top-level configuration, in order
+func (p *preparedConfigSources) mergeProfiles(v *viper.Viper) error {
+	profiles := fangs.Flatten(p.fangsCfg.Profiles...)
+	if len(profiles) == 0 {
+		return nil
+	}
+	if p.fangsCfg.ProfileKey == "" {
+		return fmt.Errorf("invalid configuration: fangs.Config.ProfileKey not defined")
+	}
+
+	all := v.AllSettings()
+	sections, ok := all[strings.ToLower(p.fangsCfg.ProfileKey)].(map[string]any)
+	if !ok || sections == nil {
+		return fmt.Errorf("'%v' not found in any configuration files", p.fangsCfg.ProfileKey)
+	}
+	for _, name := range profiles {
+		values, ok := sections[name].(map[string]any)
+		if !ok || values == nil {
+			return fmt.Errorf("profile not found in any configuration files: %v", name)
+		}
+		if err := mergo.Merge(&all, values, mergo.WithOverride, mergo.WithOverwriteWithEmptyValue); err != nil {
+			return err
+		}
+		if err := v.MergeConfigMap(all); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configBinder binds configuration fields to the flags which set them while loading configuration
+type configBinder struct {
+	sources *preparedConfigSources
+	viper   *viper.Viper
+
+	// flags are all command flags, by the address of the value each flag is bound to
+	flags map[uintptr]*pflag.Flag
+
+	// changed are the keys of all fields set by a flag, which take precedence over environment variables
+	changed map[string]struct{}
+	visited map[reflect.Value]struct{}
+}
+
+// setOverride sets the override for the key. Overrides of map entries are merged into the map, so other entries of the
+// map remain set.
+func (p *preparedConfigSources) setOverride(v *viper.Viper, key string, cfgs ...any) {
+	path := strings.Split(key, ".")
+	for i := 1; i < len(path); i++ {
+		mapKey := strings.Join(path[:i], ".")
+		field, ok := lookupConfigValue(p.fangsCfg.TagName, mapKey, cfgs...)
+		if !ok || derefValue(field).Kind() != reflect.Map {
+			continue
+		}
+		entries, _ := v.Get(mapKey).(map[string]any)
+		entries = cloneConfigValues(entries)
+		setConfigValue(entries, path[i:], p.overrides[key])
+		v.Set(mapKey, entries)
+		return
+	}
+	v.Set(key, p.overrides[key])
+}
+
+// setConfigValue sets the value at the path within the raw configuration values, creating nested maps as needed
+func setConfigValue(values map[string]any, path []string, value any) {
+	for _, name := range path[:len(path)-1] {
+		nested, ok := values[name].(map[string]any)
+		if !ok {
+			nested = map[string]any{}
+			values[name] = nested
+		}
+		values = nested
+	}
+	values[path[len(path)-1]] = value
+}
+
+// changedKey returns true if the key, or any parent of the key, is set by a flag
+func (b configBinder) changedKey(key string) bool {
+	for {
+		if _, ok := b.changed[key]; ok {
+			return true
+		}
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			return false
+		}
+		key = key[:i]
+	}
+}
+
+// bindFields binds each configuration field to the flag which sets it, descending into nested structs. Nil struct
+// pointers are allocated and recursive types are skipped, the same way fangs does.
+func (b configBinder) bindFields(ptr reflect.Value, configuring []reflect.Type, path []string) {
+	if _, ok := b.visited[ptr]; ok {
+		return
+	}
+	b.visited[ptr] = struct{}{}
+
+	addr := ptr.Pointer()
+	t := ptr.Type().Elem()
+	value := ptr.Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		value = value.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		b.bindValue(addr, path)
+		return
+	}
+
+	if !value.IsValid() {
+		// a nil embedded pointer to an unexported struct, which can't be set
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, squash, skip := configFieldName(b.sources.fangsCfg.TagName, f)
+		if skip {
+			continue
+		}
+
+		fieldPath := slices.Clone(path)
+		if !squash {
+			fieldPath = append(fieldPath, name)
+		}
+
+		fv := value.Field(i)
+		fieldConfiguring := configuring
+		if ft := f.Type; ft.Kind() == reflect.Pointer && fv.IsNil() && ft.Elem().Kind() == reflect.Struct {
+			if slices.Contains(fieldConfiguring, ft.Elem()) {
+				// don't keep allocating recursive types
+				continue
+			}
+			fieldConfiguring = append(fieldConfiguring, ft.Elem())
+			if fv.CanSet() {
+				fv.Set(reflect.New(ft.Elem()))
+			}
+		}
+
+		b.bindFields(fv.Addr(), fieldConfiguring, fieldPath)
+	}
+}
+
+// bindValue binds the key to the flag which sets the value at the address, using the transformed flag value when the
+// flag value has been transformed
+func (b configBinder) bindValue(addr uintptr, path []string) {
+	key := strings.ToLower(strings.Join(path, "."))
+
+	f, ok := b.flags[addr]
+	if !ok {
+		// the key only needs to be known for environment variables to apply
+		b.viper.SetDefault(key, nil)
+		return
+	}
+
+	_ = b.viper.BindPFlag(key, f)
+	if !f.Changed {
+		return
+	}
+	b.changed[key] = struct{}{}
+	if transformed, ok := b.sources.flagValues[f]; ok {
+		b.viper.Set(key, transformed)
+	}
+}
+
+// cloneConfigValues returns a deep copy of the raw configuration values, so merging never modifies the sources
+func cloneConfigValues(values map[string]any) map[string]any {
+	out := make(map[string]any, len(values))
+	for k, v := range values {
+		out[k] = cloneConfigValue(v)
+	}
+	return out
+}
+
+func cloneConfigValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return cloneConfigValues(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = cloneConfigValue(item)
+		}
+		return out
+	}
+	return value
+}
+
+// postLoad calls PostLoad on the config and every nested struct, slice entry, and map value which implements
+// fangs.PostLoader, parents before children, the same way fangs does
+func postLoad(v reflect.Value) error {
+	t := v.Type()
+	for t.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		if v.CanInterface() {
+			obj := v.Interface()
+			if p, ok := obj.(fangs.PostLoader); ok && !isPromotedMethod(obj, "PostLoad") {
+				if err := p.PostLoad(); err != nil {
+					return err
+				}
+			}
+		}
+		t = t.Elem()
+		v = v.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() && (!f.Anonymous || f.Type.Kind() == reflect.Pointer) {
+				continue
+			}
+			if err := postLoadValue(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := postLoadValue(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := iter.Value()
+			for entry.Kind() == reflect.Pointer && !entry.IsNil() {
+				entry = entry.Elem()
+			}
+			if entry.CanAddr() || entry.Kind() != reflect.Struct {
+				if err := postLoadValue(iter.Value()); err != nil {
+					return err
+				}
+				continue
+			}
+			// struct map entries can't be addressed, so PostLoad is called on a copy which replaces the entry
+			cp := reflect.New(entry.Type())
+			cp.Elem().Set(entry)
+			if err := postLoad(cp); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), cp.Elem())
+		}
+	}
+	return nil
+}
+
+func postLoadValue(v reflect.Value) error {
+	if isNilValue(v) {
+		return nil
+	}
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if !v.CanAddr() {
+		return nil
+	}
+	return postLoad(v.Addr())
+}
+
+func isNilValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Map, reflect.Pointer, reflect.UnsafePointer, reflect.Interface, reflect.Slice:
+		return v.IsNil()
+	default:
+	}
+	return false
+}
+
+// isPromotedMethod returns true if the method is promoted from an embedded struct, so PostLoad is only called once by
+// the struct which declares it. This relies on promoted methods being reported as autogenerated by the go runtime.
+func isPromotedMethod(o any, method string) bool {
+	m, ok := reflect.TypeOf(o).MethodByName(method)
+	if !ok {
+		return false
+	}
+	f := runtime.FuncForPC(m.Func.Pointer())
+	fileName, _ := f.FileLine(f.Entry())
+	return fileName == "<autogenerated>"
+}
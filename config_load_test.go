@@ -0,0 +1,127 @@
+package clio
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/fangs"
+	"github.com/anchore/go-logger/adapter/discard"
+)
+
+type loadNested struct {
+	Value  string            `mapstructure:"value"`
+	Labels map[string]string `mapstructure:"labels"`
+}
+
+type loadOptions struct {
+	Name      string      `mapstructure:"name"`
+	Level     string      `mapstructure:"level"`
+	Count     int         `mapstructure:"count"`
+	Names     []string    `mapstructure:"names"`
+	Nested    *loadNested `mapstructure:"nested"`
+	Token     string      `mapstructure:"token"`
+	Untouched string      `mapstructure:"untouched"`
+	loaded    []string
+}
+
+var _ fangs.PostLoader = (*loadOptions)(nil)
+var _ fangs.FlagAdder = (*loadOptions)(nil)
+
+func (o *loadOptions) PostLoad() error {
+	o.loaded = append(o.loaded, o.Name)
+	return nil
+}
+
+func (o *loadOptions) AddFlags(flags fangs.FlagSet) {
+	flags.StringVarP(&o.Name, "name", "", "the name")
+	flags.StringVarP(&o.Level, "level", "", "the level")
+	flags.StringVarP(&o.Token, "token", "", "the token")
+}
+
+func Test_preparedConfigSources_load(t *testing.T) {
+	p := &preparedConfigSources{
+		fangsCfg: fangs.Config{
+			AppName:    "my-app",
+			TagName:    "mapstructure",
+			ProfileKey: "profiles",
+			Profiles:   []string{"dev"},
+		},
+		files: []configFileValues{
+			{
+				path: "first.yaml",
+				values: map[string]any{
+					"name":   "file-name",
+					"count":  1,
+					"names":  []any{"first"},
+					"nested": map[string]any{"labels": map[string]any{"a": "first"}},
+					"profiles": map[string]any{
+						"dev": map[string]any{"level": "profile-level"},
+					},
+				},
+			},
+			{
+				path: "second.yaml",
+				values: map[string]any{
+					"count":  2,
+					"level":  "file-level",
+					"names":  []any{"second"},
+					"nested": map[string]any{"value": "second"},
+				},
+			},
+		},
+		env: map[string]string{
+			"MY_APP_NAME":            "env-name",
+			"MY_APP_COUNT":           "3",
+			"MY_APP_NESTED_LABELS_A": "env-label",
+		},
+	}
+
+	opts := &loadOptions{Untouched: "default"}
+	cmd := &cobra.Command{}
+	fangs.AddFlags(discard.New(), cmd.Flags(), opts)
+	require.NoError(t, cmd.ParseFlags([]string{"--name", "flag-name", "--token", "env:TOKEN"}))
+	p.flagValues = map[*pflag.Flag]string{cmd.Flags().Lookup("token"): "resolved-token"}
+	require.NoError(t, p.load(cmd, opts))
+
+	// flags, then environment variables, then profiles, then files in precedence order, then defaults
+	assert.Equal(t, "flag-name", opts.Name)
+	assert.Equal(t, 3, opts.Count)
+	assert.Equal(t, "profile-level", opts.Level)
+	assert.Equal(t, "second", opts.Nested.Value)
+	assert.Equal(t, "default", opts.Untouched)
+
+	// transformed flag values are loaded in place of the flag value
+	assert.Equal(t, "resolved-token", opts.Token)
+
+	// slices are appended, environment variables apply to keys only set in files
+	assert.Equal(t, []string{"first", "second"}, opts.Names)
+	assert.Equal(t, map[string]string{"a": "env-label"}, opts.Nested.Labels)
+
+	// PostLoad is called once values are loaded
+	assert.Equal(t, []string{"flag-name"}, opts.loaded)
+
+	// the sources are not modified, so may be loaded again
+	assert.Equal(t, []any{"first"}, p.files[0].values["names"])
+}
+
+type loadPrivate struct {
+	Value bool `mapstructure:"value"`
+}
+
+func Test_preparedConfigSources_load_embeddedPrivateStructPointer(t *testing.T) {
+	// the embedded unexported pointer can't be set with reflection, which is an error rather than a panic
+	opts := &struct {
+		*loadPrivate `mapstructure:",squash"`
+	}{}
+
+	p := &preparedConfigSources{
+		fangsCfg: fangs.Config{AppName: "my-app", TagName: "mapstructure"},
+		env:      map[string]string{"MY_APP_VALUE": "true"},
+	}
+	err := p.load(&cobra.Command{}, opts)
+	assert.ErrorContains(t, err, "unsupported type for squash")
+}
@@ -0,0 +1,115 @@
+package clio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/anchore/go-homedir"
+)
+
+func configMigrateCommand(internalApp *application) *cobra.Command {
+	id := internalApp.ID()
+	return &cobra.Command{
+		Use:   "migrate [PATH]",
+		Short: fmt.Sprintf("update a %s configuration file to the latest configuration version", id.Name),
+		Long: fmt.Sprintf("update a %s configuration file to the latest configuration version, by default the active configuration file. "+
+			"Comments are retained for any values which are not changed by the migration.", id.Name),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := migrateConfigPath(internalApp, args)
+			if err != nil {
+				return err
+			}
+
+			migrations := internalApp.setupConfig.configMigrations
+			latest := latestConfigVersion(migrations)
+
+			contents, version, err := migrateConfigFile(path, migrations)
+			if err != nil {
+				return err
+			}
+
+			if version == latest {
+				_, err = fmt.Fprintf(os.Stderr, "%s is already at the latest configuration version %d\n", path, latest)
+				return err
+			}
+
+			if err := replaceConfigFile(cmd, internalApp, path, contents); err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(os.Stderr, "migrated %s from configuration version %d to %d\n", path, version, latest)
+			return err
+		},
+	}
+}
+
+func migrateConfigPath(internalApp *application, args []string) (string, error) {
+	if len(args) > 0 {
+		path, err := homedir.Expand(args[0])
+		if err != nil {
+			return "", fmt.Errorf("unable to expand path: %s", args[0])
+		}
+		return path, nil
+	}
+
+	path, err := activeConfigFile(internalApp.setupConfig.FangsConfig)
+	if errors.Is(err, errNoConfigFile) {
+		return "", fmt.Errorf("%w to migrate", err)
+	}
+	return path, err
+}
+
+// migrateConfigFile returns the contents of the yaml configuration file migrated to the latest configuration version,
+// along with the original configuration version of the file
+func migrateConfigFile(path string, migrations []ConfigMigration) ([]byte, int, error) {
+	if !isYAMLFile(path) {
+		return nil, 0, fmt.Errorf("only yaml configuration files can be migrated, got: %s", path)
+	}
+
+	before, err := readConfigFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	after, err := readConfigFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	version, err := migrateConfigValues(after, migrations)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to migrate %s: %w", path, err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to read configuration file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return nil, 0, fmt.Errorf("unable to read configuration file %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		// empty document
+		doc.Kind = yaml.DocumentNode
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+
+	if err := updateYAMLMapping(doc.Content[0], before, after); err != nil {
+		return nil, 0, fmt.Errorf("unable to migrate %s: %w", path, err)
+	}
+
+	contents, err = encodeYAML(&doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	return contents, version, nil
+}
@@ -0,0 +1,132 @@
+package clio
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// configVersionKey is the configuration key holding the version of the configuration file schema
+const configVersionKey = "config-version"
+
+// ConfigMigration migrates raw configuration values from one configuration version to the next. Values are keyed by
+// the lowercase configuration key names, with nested sections as map[string]any.
+type ConfigMigration func(values map[string]any) error
+
+// latestConfigVersion returns the configuration version after all migrations have been applied, configurations
+// without a version are version 1
+func latestConfigVersion(migrations []ConfigMigration) int {
+	return len(migrations) + 1
+}
+
+// configVersion returns the version of the raw configuration values
+func configVersion(values map[string]any) (int, error) {
+	raw, ok := lookupConfigKey(values, configVersionKey)
+	if !ok {
+		return 1, nil
+	}
+
+	var version int
+	if err := mapstructure.WeakDecode(raw, &version); err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid %s: %v", configVersionKey, raw)
+	}
+	return version, nil
+}
+
+// migrateConfigValues applies all migrations needed to bring the values to the latest configuration version, returning
+// the original version of the values
+func migrateConfigValues(values map[string]any, migrations []ConfigMigration) (int, error) {
+	version, err := configVersion(values)
+	if err != nil {
+		return 0, err
+	}
+
+	latest := latestConfigVersion(migrations)
+	if version > latest {
+		return version, fmt.Errorf("%s %d is newer than the latest supported version %d", configVersionKey, version, latest)
+	}
+
+	for v := version; v < latest; v++ {
+		if err := migrations[v-1](values); err != nil {
+			return version, fmt.Errorf("unable to migrate configuration from version %d to %d: %w", v, v+1, err)
+		}
+	}
+
+	if version != latest {
+		values[configVersionKey] = latest
+	}
+	return version, nil
+}
+
+// migrationTransform migrates configuration files to the latest configuration version before loading
+func migrationTransform(migrations []ConfigMigration) configFileTransform {
+	return func(_ string, values map[string]any) (bool, error) {
+		version, err := migrateConfigValues(values, migrations)
+		return version != latestConfigVersion(migrations), err
+	}
+}
+
+// updateYAMLMapping updates the yaml mapping node to match the after values, only replacing nodes for values which
+// differ from the before values so comments are retained for any unchanged values
+func updateYAMLMapping(node *yaml.Node, before, after map[string]any) error {
+	// remove keys which no longer exist
+	for i := 0; i+1 < len(node.Content); {
+		if _, ok := after[strings.ToLower(node.Content[i].Value)]; !ok {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			continue
+		}
+		i += 2
+	}
+
+	keys := make([]string, 0, len(after))
+	for key := range after {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value := after[key]
+		previous, existed := before[key]
+		if existed && reflect.DeepEqual(previous, value) {
+			continue
+		}
+
+		idx := yamlMappingIndex(node, key)
+
+		previousMap, wasMap := previous.(map[string]any)
+		valueMap, isMap := value.(map[string]any)
+		if idx >= 0 && wasMap && isMap && node.Content[idx+1].Kind == yaml.MappingNode {
+			if err := updateYAMLMapping(node.Content[idx+1], previousMap, valueMap); err != nil {
+				return err
+			}
+			continue
+		}
+
+		valueNode := &yaml.Node{}
+		if err := valueNode.Encode(value); err != nil {
+			return fmt.Errorf("unable to encode value for %q: %w", key, err)
+		}
+
+		if idx < 0 {
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+			if key == configVersionKey {
+				// the configuration version is the first key, so it is easy to find
+				node.Content = append([]*yaml.Node{keyNode, valueNode}, node.Content...)
+				continue
+			}
+			node.Content = append(node.Content, keyNode, valueNode)
+			continue
+		}
+
+		existing := node.Content[idx+1]
+		valueNode.HeadComment = existing.HeadComment
+		valueNode.LineComment = existing.LineComment
+		valueNode.FootComment = existing.FootComment
+		node.Content[idx+1] = valueNode
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package clio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMigrations moves the top-level "token" to "registry.token" (v1 -> v2), then renames "registry" to "registries"
+// with a list of registries (v2 -> v3)
+var testMigrations = []ConfigMigration{
+	func(values map[string]any) error {
+		if token, ok := removeConfigKey(values, "token"); ok {
+			setConfigKey(values, token, "registry", "token")
+		}
+		return nil
+	},
+	func(values map[string]any) error {
+		registry, ok := removeConfigKey(values, "registry")
+		if !ok {
+			return nil
+		}
+		if _, ok := registry.(map[string]any); !ok {
+			return errors.New("registry must be a mapping")
+		}
+		values["registries"] = []any{registry}
+		return nil
+	},
+}
+
+func Test_migrateConfigValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      map[string]any
+		want        map[string]any
+		wantVersion int
+		wantErr     require.ErrorAssertionFunc
+	}{
+		{
+			name:        "no version",
+			values:      map[string]any{"token": "the-token"},
+			want:        map[string]any{"config-version": 3, "registries": []any{map[string]any{"token": "the-token"}}},
+			wantVersion: 1,
+		},
+		{
+			name:        "intermediate version",
+			values:      map[string]any{"config-version": "2", "registry": map[string]any{"token": "the-token"}},
+			want:        map[string]any{"config-version": 3, "registries": []any{map[string]any{"token": "the-token"}}},
+			wantVersion: 2,
+		},
+		{
+			name:        "latest version",
+			values:      map[string]any{"config-version": 3, "name": "name"},
+			want:        map[string]any{"config-version": 3, "name": "name"},
+			wantVersion: 3,
+		},
+		{
+			name:    "newer version",
+			values:  map[string]any{"config-version": 4},
+			wantErr: require.Error,
+		},
+		{
+			name:    "invalid version",
+			values:  map[string]any{"config-version": "latest"},
+			wantErr: require.Error,
+		},
+		{
+			name:    "migration error",
+			values:  map[string]any{"config-version": 2, "registry": "invalid"},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			version, err := migrateConfigValues(tt.values, testMigrations)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantVersion, version)
+			assert.Equal(t, tt.want, tt.values)
+		})
+	}
+}
+
+func newMigrationsApp(t *testing.T, contents string) (*application, string) {
+	t.Helper()
+
+	cfg := NewSetupConfig(Identification{Name: "my-app"}).WithConfigMigrations(testMigrations...)
+	return newConfigTestApp(t, cfg, map[string]string{".my-app.yaml": contents}, ".my-app.yaml")
+}
+
+func Test_ConfigMigrationsLoaded(t *testing.T) {
+	app, configFile := newMigrationsApp(t, "name: the-name\ntoken: the-token\n")
+
+	opts := &configTestOptions{}
+	_, err := app.loadConfigs(&cobra.Command{}, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "the-name", opts.Name)
+	require.Len(t, opts.Registries, 1)
+	assert.Equal(t, "the-token", opts.Registries[0].Token)
+
+	// the configuration file is not modified when loading
+	contents, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, "name: the-name\ntoken: the-token\n", string(contents))
+}
+
+func Test_ConfigMigrateCommand(t *testing.T) {
+	app, configFile := newMigrationsApp(t, `# the name to use
+name: the-name # inline comment
+# the token to use
+token: the-token
+`)
+	_ = app.SetupCommand(&cobra.Command{}, &configTestOptions{})
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeMigrateSubcommand(true))
+	migrateCmd, _, err := configCmd.Find([]string{"migrate"})
+	require.NoError(t, err)
+
+	_, stderr := captureStd(func() {
+		require.NoError(t, migrateCmd.RunE(migrateCmd, nil))
+	})
+	assert.Equal(t, "migrated "+configFile+" from configuration version 1 to 3\n", stderr)
+
+	contents, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, `config-version: 3
+# the name to use
+name: the-name # inline comment
+registries:
+  - token: the-token
+`, string(contents))
+
+	_, stderr = captureStd(func() {
+		require.NoError(t, migrateCmd.RunE(migrateCmd, nil))
+	})
+	assert.Equal(t, configFile+" is already at the latest configuration version 3\n", stderr)
+}
+
+func Test_ConfigMigrateCommand_path(t *testing.T) {
+	app, _ := newMigrationsApp(t, "name: the-name\n")
+	_ = app.SetupCommand(&cobra.Command{}, &configTestOptions{})
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeMigrateSubcommand(true))
+	migrateCmd, _, err := configCmd.Find([]string{"migrate"})
+	require.NoError(t, err)
+
+	// a file which is not the active configuration file is validated by itself
+	invalid := "name:\n  nested: value\ntoken: the-token\n"
+	other := filepath.Join(t.TempDir(), "other.yaml")
+	require.NoError(t, os.WriteFile(other, []byte(invalid), 0o600))
+
+	_, _ = captureStd(func() {
+		require.Error(t, migrateCmd.RunE(migrateCmd, []string{other}))
+	})
+
+	contents, err := os.ReadFile(other)
+	require.NoError(t, err)
+	assert.Equal(t, invalid, string(contents))
+
+	require.NoError(t, os.WriteFile(other, []byte("token: the-token\n"), 0o600))
+	_, stderr := captureStd(func() {
+		require.NoError(t, migrateCmd.RunE(migrateCmd, []string{other}))
+	})
+	assert.Equal(t, "migrated "+other+" from configuration version 1 to 3\n", stderr)
+}
+
+func Test_defaultConfigContents_configVersion(t *testing.T) {
+	app, _ := newMigrationsApp(t, "")
+	cmd := &cobra.Command{}
+	type options struct {
+		Name string `mapstructure:"name"`
+	}
+	contents := defaultConfigContents(cmd, app, DefaultConfigCommandConfig(), []any{&options{Name: "default-name"}})
+	assert.Equal(t, "config-version: 3\n\n# (env: MY_APP_NAME)\nname: 'default-name'\n", contents)
+}
@@ -0,0 +1,78 @@
+package clio
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// configOverrideFlag is the name of the global flag used to set arbitrary configuration values
+const configOverrideFlag = "set"
+
+// parseConfigOverride splits a KEY=VALUE configuration override
+func parseConfigOverride(override string) (string, string, error) {
+	key, value, ok := strings.Cut(override, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid --%s value %q, expected KEY=VALUE", configOverrideFlag, override)
+	}
+	return key, value, nil
+}
+
+// configOverrideKeys returns the lowercase keys of all valid configuration overrides
+func configOverrideKeys(overrides []string) []string {
+	var keys []string
+	for _, override := range overrides {
+		if key, _, err := parseConfigOverride(override); err == nil {
+			keys = append(keys, strings.ToLower(key))
+		}
+	}
+	return keys
+}
+
+// applyConfigOverrides verifies each KEY=VALUE override can be decoded into the configuration field for the key, or
+// the value type of a map field for sub-keys of the map (e.g. log.levels.eventloop), then records the value to load with
+// precedence over configuration files and environment variables. The environment itself is never modified.
+func (p *preparedConfigSources) applyConfigOverrides(overrides []string, cfgs ...any) error {
+	var errs []error
+	for _, override := range overrides {
+		key, value, err := parseConfigOverride(override)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		field, ok := lookupConfigValue(p.fangsCfg.TagName, key, cfgs...)
+		if !ok || derefValue(field).Kind() == reflect.Struct {
+			errs = append(errs, fmt.Errorf("invalid --%s value: unknown configuration key %q", configOverrideFlag, key))
+			continue
+		}
+
+		if _, err := decodeConfigValue(value, field.Type()); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --%s value for %q: %w", configOverrideFlag, key, err))
+			continue
+		}
+
+		p.overrides[strings.ToLower(key)] = value
+	}
+	return errors.Join(errs...)
+}
+
+// transformOverrides applies the transforms to the values of all configuration overrides
+func (p *preparedConfigSources) transformOverrides(transforms []configValueTransform) error {
+	if len(transforms) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, key := range sortedKeys(p.overrides) {
+		transformed, _, err := applyValueTransforms(p.overrides[key], transforms)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid --%s value for %q: %w", configOverrideFlag, key, err))
+			continue
+		}
+		p.overrides[key] = transformed
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,163 @@
+package clio
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+)
+
+func Test_parseConfigOverride(t *testing.T) {
+	tests := []struct {
+		override  string
+		wantKey   string
+		wantValue string
+		wantErr   require.ErrorAssertionFunc
+	}{
+		{override: "log.level=debug", wantKey: "log.level", wantValue: "debug"},
+		{override: "name=a=b", wantKey: "name", wantValue: "a=b"},
+		{override: "name=", wantKey: "name", wantValue: ""},
+		{override: "name", wantErr: require.Error},
+		{override: "=value", wantErr: require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.override, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			key, value, err := parseConfigOverride(tt.override)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantKey, key)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+}
+
+func newOverridesApp(t *testing.T, args ...string) (*application, *cobra.Command) {
+	t.Helper()
+
+	cfg := NewSetupConfig(Identification{Name: "my-app"}).WithGlobalSetFlag().WithGlobalLoggingFlags()
+	app, _ := newConfigTestApp(t, cfg, map[string]string{
+		".my-app.yaml": "name: file-name\ncount: 1\nlimits: {cpu: 1}\n",
+	}, ".my-app.yaml")
+
+	root := app.SetupRootCommand(&cobra.Command{})
+	sub := app.SetupCommand(&cobra.Command{Use: "sub"}, &configTestOptions{})
+	root.AddCommand(sub)
+
+	require.NoError(t, sub.ParseFlags(args))
+	return app, sub
+}
+
+func Test_ConfigOverrides(t *testing.T) {
+	t.Setenv("MY_APP_NAME", "env-name")
+
+	app, cmd := newOverridesApp(t,
+		"--set", "name=set-name",
+		"--set", "registry.insecure=true",
+		"--set", "log.level=debug",
+	)
+
+	opts := &configTestOptions{}
+	_, err := app.loadConfigs(cmd, opts)
+	require.NoError(t, err)
+
+	// overrides take precedence over environment variables and configuration files
+	assert.Equal(t, "set-name", opts.Name)
+	assert.Equal(t, 1, opts.Count)
+	assert.True(t, opts.Registry.Insecure)
+	assert.Equal(t, logger.DebugLevel, app.state.Config.Log.Level)
+}
+
+func Test_ConfigOverrides_flags(t *testing.T) {
+	app, cmd := newOverridesApp(t, "--set", "log.quiet=false", "-q")
+
+	_, err := app.loadConfigs(cmd, &configTestOptions{})
+	require.NoError(t, err)
+
+	// flags take precedence over overrides
+	assert.True(t, app.state.Config.Log.Quiet)
+}
+
+func Test_ConfigOverrides_mapKeys(t *testing.T) {
+	app, cmd := newOverridesApp(t,
+		"--set", "limits.memory=512",
+		"--set", "log.levels.eventloop=trace",
+	)
+
+	opts := &configTestOptions{}
+	_, err := app.loadConfigs(cmd, opts)
+	require.NoError(t, err)
+
+	// map entries from configuration files are kept
+	assert.Equal(t, map[string]int{"cpu": 1, "memory": 512}, opts.Limits)
+	assert.Equal(t, LogLevels{"eventloop": logger.TraceLevel}, app.state.Config.Log.Levels)
+}
+
+func Test_ConfigOverrides_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "unknown key",
+			args:    []string{"--set", "nmae=value"},
+			wantErr: `unknown configuration key "nmae"`,
+		},
+		{
+			name:    "invalid type",
+			args:    []string{"--set", "count=many"},
+			wantErr: `invalid --set value for "count"`,
+		},
+		{
+			name:    "missing value",
+			args:    []string{"--set", "count"},
+			wantErr: `expected KEY=VALUE`,
+		},
+		{
+			name:    "invalid map value type",
+			args:    []string{"--set", "limits.memory=lots"},
+			wantErr: `invalid --set value for "limits.memory"`,
+		},
+		{
+			name:    "map value key",
+			args:    []string{"--set", "limits.memory.max=1"},
+			wantErr: `unknown configuration key "limits.memory.max"`,
+		},
+		{
+			name:    "section",
+			args:    []string{"--set", "registry=value"},
+			wantErr: `unknown configuration key "registry"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, cmd := newOverridesApp(t, tt.args...)
+			_, err := app.loadConfigs(cmd, &configTestOptions{})
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_ConfigExplainCommand_overrides(t *testing.T) {
+	app, _ := newOverridesApp(t, "--set", "count=5")
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeExplainSubcommand(true))
+	explainCmd, _, err := configCmd.Find([]string{"explain"})
+	require.NoError(t, err)
+
+	stdout, _ := captureStd(func() {
+		require.NoError(t, explainCmd.RunE(explainCmd, nil))
+	})
+
+	rows := explainRows(stdout)
+	assert.Equal(t, []string{"count", "5", "flag --set"}, rows["count"])
+	assert.Equal(t, []string{"name", "'file-name'", "file " + app.setupConfig.FangsConfig.Files[0]}, rows["name"])
+}
@@ -0,0 +1,142 @@
+package clio
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/anchore/go-homedir"
+)
+
+const (
+	// pathTagOption marks configuration fields holding file paths, which have `~` expanded to the home directory
+	pathTagOption = "path"
+
+	// configRelativeTagOption marks path fields which, when set in a configuration file, are relative to the directory
+	// of that file instead of the working directory
+	configRelativeTagOption = "config-relative"
+)
+
+func isPathField(f reflect.StructField) bool {
+	return hasClioTagOption(f, pathTagOption) || hasClioTagOption(f, configRelativeTagOption)
+}
+
+// configRelativePathKeys returns the lowercase keys of all config-relative path fields within the configs
+func configRelativePathKeys(tagName string, cfgs ...any) []string {
+	var keys []string
+	for _, cfg := range cfgs {
+		visitConfigFields(tagName, cfg, func(path []string, f reflect.StructField, _ reflect.Value) {
+			key := strings.ToLower(strings.Join(path, "."))
+			if hasClioTagOption(f, configRelativeTagOption) && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		})
+	}
+	return keys
+}
+
+// configRelativePathTransform resolves relative paths set in configuration files for the keys, including within
+// profiles, relative to the directory of the configuration file
+func (p *preparedConfigSources) configRelativePathTransform(keys []string) configFileTransform {
+	return func(path string, values map[string]any) (bool, error) {
+		if !fileExists(path) {
+			// not a file on disk, such as configuration read from stdin
+			return false, nil
+		}
+		dir, err := filepath.Abs(filepath.Dir(path))
+		if err != nil {
+			return false, err
+		}
+
+		sections := []map[string]any{values}
+		if p.fangsCfg.ProfileKey != "" {
+			if profiles, ok := values[strings.ToLower(p.fangsCfg.ProfileKey)].(map[string]any); ok {
+				for _, profile := range profiles {
+					if section, ok := profile.(map[string]any); ok {
+						sections = append(sections, section)
+					}
+				}
+			}
+		}
+
+		changed := false
+		for _, section := range sections {
+			for _, key := range keys {
+				keyPath := configKeyPath(key)
+				value, ok := lookupConfigKey(section, keyPath...)
+				if !ok {
+					continue
+				}
+				resolved, modified := resolveRelativePaths(dir, value)
+				if modified {
+					setConfigKey(section, resolved, keyPath...)
+					changed = true
+				}
+			}
+		}
+		return changed, nil
+	}
+}
+
+// resolveRelativePaths resolves the path, or list of paths, relative to the directory
+func resolveRelativePaths(dir string, value any) (any, bool) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return v, false
+		}
+		expanded, err := homedir.Expand(v)
+		if err != nil || filepath.IsAbs(expanded) {
+			return v, false
+		}
+		return filepath.Join(dir, expanded), true
+	case []any:
+		changed := false
+		for i, item := range v {
+			resolved, modified := resolveRelativePaths(dir, item)
+			v[i] = resolved
+			changed = changed || modified
+		}
+		return v, changed
+	}
+	return value, false
+}
+
+// expandPathFields expands `~` to the home directory in all path fields of the loaded configs, including path fields
+// of structs within slices (such as log sinks)
+func expandPathFields(tagName string, cfgs ...any) {
+	for _, cfg := range cfgs {
+		visitConfigFields(tagName, cfg, func(_ []string, f reflect.StructField, v reflect.Value) {
+			if isPathField(f) {
+				expandPaths(v)
+				return
+			}
+
+			v = derefValue(v)
+			if v.Kind() != reflect.Slice {
+				return
+			}
+			for i := 0; i < v.Len(); i++ {
+				if elem := derefValue(v.Index(i)); elem.Kind() == reflect.Struct && elem.CanAddr() {
+					expandPathFields(tagName, elem.Addr().Interface())
+				}
+			}
+		})
+	}
+}
+
+func expandPaths(v reflect.Value) {
+	v = derefValue(v)
+	switch v.Kind() {
+	case reflect.String:
+		if expanded, err := homedir.Expand(v.String()); err == nil && v.CanSet() && expanded != v.String() {
+			v.SetString(expanded)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			expandPaths(v.Index(i))
+		}
+	default:
+	}
+}
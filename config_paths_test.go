@@ -0,0 +1,134 @@
+package clio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-homedir"
+)
+
+func Test_resolveRelativePaths(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       any
+		want        any
+		wantChanged bool
+	}{
+		{name: "relative", value: "certs/ca.pem", want: filepath.Join("/config", "certs/ca.pem"), wantChanged: true},
+		{name: "absolute", value: "/etc/ca.pem", want: "/etc/ca.pem"},
+		{name: "home", value: "~/ca.pem", want: "~/ca.pem"},
+		{name: "empty", value: "", want: ""},
+		{name: "not a path", value: 3, want: 3},
+		{
+			name:        "list",
+			value:       []any{"a.pem", "/b.pem", "~/c.pem"},
+			want:        []any{filepath.Join("/config", "a.pem"), "/b.pem", "~/c.pem"},
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := resolveRelativePaths("/config", tt.value)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantChanged, changed)
+		})
+	}
+}
+
+type pathOptions struct {
+	Name     string   `mapstructure:"name"`
+	Cache    string   `mapstructure:"cache" clio:"path"`
+	CA       string   `mapstructure:"ca" clio:"config-relative"`
+	Includes []string `mapstructure:"includes" clio:"path,config-relative"`
+	Output   string   `mapstructure:"output" clio:"path"`
+
+	// loadedCache is the cache path seen by PostLoad
+	loadedCache string
+}
+
+func (o *pathOptions) PostLoad() error {
+	o.loadedCache = o.Cache
+	return nil
+}
+
+func Test_PathFields(t *testing.T) {
+	home, err := homedir.Dir()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"config/.my-app.yaml": `name: ~/not-a-path
+cache: ~/.cache/my-app
+ca: certs/ca.pem
+includes: [a.yaml, ~/b.yaml, /c.yaml]
+output: relative/to/cwd
+profiles:
+  ci:
+    ca: ci/ca.pem
+`,
+	})
+	configFile := filepath.Join(dir, "config", ".my-app.yaml")
+
+	cfg := NewSetupConfig(Identification{Name: "my-app"})
+	cfg.FangsConfig.Files = []string{configFile}
+	cfg.FangsConfig.Profiles = []string{"ci"}
+	app := New(*cfg).(*application)
+
+	opts := &pathOptions{}
+	_, err = app.loadConfigs(&cobra.Command{}, opts)
+	require.NoError(t, err)
+
+	configDir := filepath.Join(dir, "config")
+	assert.Equal(t, "~/not-a-path", opts.Name)
+	assert.Equal(t, filepath.Join(home, ".cache/my-app"), opts.Cache)
+	assert.Equal(t, opts.Cache, opts.loadedCache)
+	assert.Equal(t, filepath.Join(configDir, "ci/ca.pem"), opts.CA)
+	assert.Equal(t, []string{filepath.Join(configDir, "a.yaml"), filepath.Join(home, "b.yaml"), "/c.yaml"}, opts.Includes)
+	assert.Equal(t, "relative/to/cwd", opts.Output)
+
+	// the configuration file is not modified
+	contents, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "ca: certs/ca.pem")
+}
+
+func Test_PathFields_env(t *testing.T) {
+	home, err := homedir.Dir()
+	require.NoError(t, err)
+
+	t.Setenv("MY_APP_CA", "relative/ca.pem")
+	t.Setenv("MY_APP_CACHE", "~/cache")
+
+	app := New(*NewSetupConfig(Identification{Name: "my-app"})).(*application)
+
+	opts := &pathOptions{}
+	_, err = app.loadConfigs(&cobra.Command{}, opts)
+	require.NoError(t, err)
+
+	// paths outside of configuration files remain relative to the working directory
+	assert.Equal(t, "relative/ca.pem", opts.CA)
+	assert.Equal(t, filepath.Join(home, "cache"), opts.Cache)
+}
+
+func Test_expandPathFields_logging(t *testing.T) {
+	home, err := homedir.Dir()
+	require.NoError(t, err)
+
+	cfg := &Config{Log: &LoggingConfig{
+		FileLocation: "~/my-app-{date}.log",
+		Sinks: []LogSink{
+			{Type: LogSinkFile, Path: "~/my-app.json"},
+			{Type: LogSinkSyslog, Tag: "~/not-a-path"},
+		},
+	}}
+	expandPathFields("mapstructure", cfg)
+
+	assert.Equal(t, filepath.Join(home, "my-app-{date}.log"), cfg.Log.FileLocation)
+	assert.Equal(t, filepath.Join(home, "my-app.json"), cfg.Log.Sinks[0].Path)
+	assert.Equal(t, "~/not-a-path", cfg.Log.Sinks[1].Tag)
+}
@@ -0,0 +1,57 @@
+package clio
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anchore/go-homedir"
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+const (
+	envSecretPrefix  = "env:"
+	fileSecretPrefix = "file:"
+)
+
+// secretReferenceTransform resolves secret references in configuration values, adding each resolved secret to the
+// redact store so it is never shown in logs or configuration output
+func secretReferenceTransform(store redact.Store, lookupEnv lookupEnvFunc) configValueTransform {
+	return func(value string) (string, bool, error) {
+		secret, ok, err := resolveSecretReference(value, lookupEnv)
+		if !ok || err != nil {
+			return value, false, err
+		}
+		if store != nil && secret != "" {
+			store.Add(secret)
+		}
+		return secret, true, nil
+	}
+}
+
+// resolveSecretReference returns the secret referenced by the value, either an environment variable (env:NAME) or
+// the contents of a file (file:/path/to/secret), returning false if the value is not a secret reference
+func resolveSecretReference(value string, lookupEnv lookupEnvFunc) (string, bool, error) {
+	switch {
+	case strings.HasPrefix(value, envSecretPrefix):
+		name := strings.TrimPrefix(value, envSecretPrefix)
+		secret, ok := lookupEnv(name)
+		if !ok {
+			return "", true, fmt.Errorf("unable to resolve secret reference %q: environment variable is not set", value)
+		}
+		return secret, true, nil
+
+	case strings.HasPrefix(value, fileSecretPrefix):
+		path, err := homedir.Expand(strings.TrimPrefix(value, fileSecretPrefix))
+		if err != nil {
+			return "", true, fmt.Errorf("unable to resolve secret reference %q: %w", value, err)
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return "", true, fmt.Errorf("unable to resolve secret reference %q: %w", value, err)
+		}
+		// files commonly end with a newline which is not part of the secret
+		return strings.TrimRight(string(contents), "\r\n"), true, nil
+	}
+	return value, false, nil
+}
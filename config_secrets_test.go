@@ -0,0 +1,152 @@
+package clio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/fangs"
+)
+
+func Test_resolveSecretReference(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-secret\n"), 0o600))
+
+	t.Setenv("SECRET_TOKEN", "env-secret")
+
+	tests := []struct {
+		value   string
+		want    string
+		wantRef bool
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			value: "plain-value",
+			want:  "plain-value",
+		},
+		{
+			value:   "env:SECRET_TOKEN",
+			want:    "env-secret",
+			wantRef: true,
+		},
+		{
+			value:   "file:" + secretFile,
+			want:    "file-secret",
+			wantRef: true,
+		},
+		{
+			value:   "env:MISSING_SECRET_TOKEN",
+			wantRef: true,
+			wantErr: require.Error,
+		},
+		{
+			value:   "file:" + filepath.Join(t.TempDir(), "missing"),
+			wantRef: true,
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, ref, err := resolveSecretReference(tt.value, os.LookupEnv)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.wantRef, ref)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type secretOptions struct {
+	Token    string   `mapstructure:"token"`
+	Password string   `mapstructure:"password"`
+	Name     string   `mapstructure:"name"`
+	Keys     []string `mapstructure:"keys"`
+	loaded   secretOptionsLoaded
+}
+
+// secretOptionsLoaded are the values seen by PostLoad
+type secretOptionsLoaded struct {
+	token    string
+	password string
+}
+
+var _ fangs.PostLoader = (*secretOptions)(nil)
+var _ fangs.FlagAdder = (*secretOptions)(nil)
+
+func (o *secretOptions) PostLoad() error {
+	o.loaded = secretOptionsLoaded{
+		token:    o.Token,
+		password: o.Password,
+	}
+	return nil
+}
+
+func (o *secretOptions) AddFlags(flags fangs.FlagSet) {
+	flags.StringVarP(&o.Name, "name", "", "the name")
+}
+
+func Test_SecretReferences(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-password\n"), 0o600))
+
+	configFile := filepath.Join(dir, ".my-app.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`password: file:`+secretFile+`
+keys:
+  - env:SECRET_KEY
+  - plain-key
+`), 0o600))
+
+	t.Setenv("SECRET_TOKEN", "env-token")
+	t.Setenv("SECRET_KEY", "env-key")
+	t.Setenv("SECRET_NAME", "env-name")
+	t.Setenv("MY_APP_TOKEN", "env:SECRET_TOKEN")
+
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	}).WithSecretReferences()
+	cfg.FangsConfig.Files = []string{configFile}
+
+	app := New(*cfg)
+	opts := &secretOptions{}
+	cmd := app.SetupCommand(&cobra.Command{}, opts)
+	require.NoError(t, cmd.ParseFlags([]string{"--name", "env:SECRET_NAME"}))
+
+	_, err := app.(*application).loadConfigs(cmd, opts)
+	require.NoError(t, err)
+
+	// secrets are resolved before PostLoad is called
+	assert.Equal(t, secretOptionsLoaded{token: "env-token", password: "file-password"}, opts.loaded)
+	assert.Equal(t, "env-token", opts.Token)
+	assert.Equal(t, "file-password", opts.Password)
+	assert.Equal(t, "env-name", opts.Name)
+	assert.Equal(t, []string{"env-key", "plain-key"}, opts.Keys)
+	assert.Equal(t, "env:SECRET_TOKEN", os.Getenv("MY_APP_TOKEN"))
+
+	store := app.(*application).State().RedactStore
+	assert.Equal(t, "token=******* password=******* key=******* name=*******",
+		store.RedactString("token=env-token password=file-password key=env-key name=env-name"))
+}
+
+func Test_SecretReferences_disabled(t *testing.T) {
+	t.Setenv("SECRET_TOKEN", "env-token")
+	t.Setenv("MY_APP_TOKEN", "env:SECRET_TOKEN")
+
+	app := New(*NewSetupConfig(Identification{
+		Name: "my-app",
+	}))
+	opts := &secretOptions{}
+	_, err := app.(*application).loadConfigs(&cobra.Command{}, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "env:SECRET_TOKEN", opts.Token)
+}
@@ -0,0 +1,152 @@
+package clio
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+// clioTagName is the struct tag used for clio-specific field options, e.g. `clio:"sensitive"`
+const clioTagName = "clio"
+
+// sensitiveTagOption marks configuration fields holding sensitive values, which are redacted wherever they appear
+const sensitiveTagOption = "sensitive"
+
+// SensitiveFieldDescriber a struct implementing this interface will have DescribeSensitiveFields called after the
+// configuration is loaded, allowing fields to be marked as sensitive without a struct tag
+type SensitiveFieldDescriber interface {
+	DescribeSensitiveFields(fields SensitiveFieldSet)
+}
+
+// SensitiveFieldSet accepts pointers to configuration fields which hold sensitive values
+type SensitiveFieldSet interface {
+	Add(ptrs ...any)
+}
+
+type sensitiveField struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type sensitiveFieldSet map[sensitiveField]struct{}
+
+var _ SensitiveFieldSet = (sensitiveFieldSet)(nil)
+
+func (s sensitiveFieldSet) Add(ptrs ...any) {
+	for _, ptr := range ptrs {
+		v := reflect.ValueOf(ptr)
+		if v.Kind() != reflect.Pointer {
+			panic(fmt.Sprintf("Add() requires a pointer, but got: %#v", ptr))
+		}
+		s[sensitiveField{ptr: v.Pointer(), typ: v.Type().Elem()}] = struct{}{}
+	}
+}
+
+func (s sensitiveFieldSet) contains(v reflect.Value) bool {
+	if !v.CanAddr() {
+		return false
+	}
+	_, ok := s[sensitiveField{ptr: v.Addr().Pointer(), typ: v.Type()}]
+	return ok
+}
+
+// hasClioTagOption returns true if the field has the option in the clio struct tag, e.g. `clio:"sensitive"`
+func hasClioTagOption(f reflect.StructField, option string) bool {
+	return slices.Contains(strings.Split(f.Tag.Get(clioTagName), ","), option)
+}
+
+// redactSensitiveValues adds the values of all sensitive fields to the redact store, fields are sensitive when tagged
+// with `clio:"sensitive"` or described by a SensitiveFieldDescriber
+func redactSensitiveValues(store redact.Store, tagName string, cfgs ...any) {
+	if store == nil {
+		return
+	}
+
+	isSensitive := sensitiveFieldMatcher(cfgs...)
+	for _, cfg := range cfgs {
+		visitConfigFields(tagName, cfg, func(_ []string, f reflect.StructField, v reflect.Value) {
+			if isSensitive(f, v) {
+				redactValue(store, v)
+			}
+		})
+	}
+}
+
+// sensitiveFieldMatcher returns a function reporting whether a configuration field within the configs is sensitive
+func sensitiveFieldMatcher(cfgs ...any) func(f reflect.StructField, v reflect.Value) bool {
+	described := sensitiveFieldSet{}
+	for _, cfg := range cfgs {
+		describeSensitiveFields(described, reflect.ValueOf(cfg), sensitiveFieldSet{})
+	}
+
+	return func(f reflect.StructField, v reflect.Value) bool {
+		return hasClioTagOption(f, sensitiveTagOption) || described.contains(v)
+	}
+}
+
+// describeSensitiveFields calls DescribeSensitiveFields on the value and all nested structs
+func describeSensitiveFields(fields sensitiveFieldSet, v reflect.Value, visited sensitiveFieldSet) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		// nested structs may share an address with the parent, so are identified by both address and type
+		key := sensitiveField{ptr: v.Pointer(), typ: v.Type()}
+		if _, ok := visited[key]; ok {
+			return
+		}
+		visited[key] = struct{}{}
+		if d, ok := v.Interface().(SensitiveFieldDescriber); ok {
+			d.DescribeSensitiveFields(fields)
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct && field.CanAddr() {
+			field = field.Addr()
+		}
+		describeSensitiveFields(fields, field, visited)
+	}
+}
+
+// redactValue adds all string values held by the value to the redact store
+func redactValue(store redact.Store, v reflect.Value) {
+	for _, s := range stringValues(v) {
+		store.Add(s)
+	}
+}
+
+// stringValues returns all non-empty strings held by the value, including within slices and map values
+func stringValues(v reflect.Value) []string {
+	v = derefValue(v)
+	var out []string
+	switch v.Kind() {
+	case reflect.String:
+		if v.String() != "" {
+			out = append(out, v.String())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			out = append(out, stringValues(v.Index(i))...)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			out = append(out, stringValues(iter.Value())...)
+		}
+	default:
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package clio
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+type sensitiveOptions struct {
+	Registry registryOptions `mapstructure:"registry"`
+	Token    string          `mapstructure:"token" clio:"sensitive"`
+	Keys     []string        `mapstructure:"keys" clio:"sensitive"`
+	Name     string          `mapstructure:"name"`
+}
+
+type registryOptions struct {
+	// the first field shares an address with the parent struct
+	Password string            `mapstructure:"password"`
+	Headers  map[string]string `mapstructure:"headers"`
+}
+
+var _ SensitiveFieldDescriber = (*registryOptions)(nil)
+
+func (o *registryOptions) DescribeSensitiveFields(fields SensitiveFieldSet) {
+	fields.Add(&o.Password, &o.Headers)
+}
+
+func Test_redactSensitiveValues(t *testing.T) {
+	store := redact.NewStore()
+	redactSensitiveValues(store, "mapstructure", &sensitiveOptions{
+		Registry: registryOptions{
+			Password: "registry-password",
+			Headers:  map[string]string{"Authorization": "bearer-token"},
+		},
+		Token: "the-token",
+		Keys:  []string{"key-1", "key-2"},
+		Name:  "not-sensitive",
+	})
+
+	assert.Equal(t,
+		"******* ******* ******* ******* ******* not-sensitive",
+		store.RedactString("registry-password bearer-token the-token key-1 key-2 not-sensitive"),
+	)
+}
+
+func Test_SensitiveFieldsLoaded(t *testing.T) {
+	t.Setenv("MY_APP_TOKEN", "env-token")
+	t.Setenv("MY_APP_REGISTRY_PASSWORD", "env-password")
+
+	app := New(*NewSetupConfig(Identification{
+		Name: "my-app",
+	}))
+
+	opts := &sensitiveOptions{}
+	_ = app.SetupCommand(&cobra.Command{}, opts)
+	_, err := app.(*application).loadConfigs(&cobra.Command{}, opts)
+	require.NoError(t, err)
+
+	store := app.(*application).State().RedactStore
+	assert.Equal(t, "******* *******", store.RedactString("env-token env-password"))
+}
+
+func Test_ConfigCommandSensitiveDefaults(t *testing.T) {
+	app := New(*NewSetupConfig(Identification{
+		Name: "my-app",
+	}))
+
+	_ = app.SetupCommand(&cobra.Command{}, &sensitiveOptions{
+		Token: "default-token",
+		Name:  "default-name",
+	})
+
+	stdout, _ := captureStd(func() {
+		configCmd := ConfigCommand(app, nil)
+		require.NoError(t, configCmd.RunE(configCmd, nil))
+	})
+
+	require.Contains(t, stdout, "token: '*******'")
+	require.Contains(t, stdout, "name: 'default-name'")
+	require.NotContains(t, stdout, "default-token")
+}
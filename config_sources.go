@@ -0,0 +1,266 @@
+package clio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/anchore/fangs"
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+// configValueTransform rewrites a single raw configuration value before it is loaded, returning true if the value
+// was changed
+type configValueTransform func(value string) (string, bool, error)
+
+// configFileTransform modifies the raw values read from a configuration file before it is loaded, returning true if
+// any values were changed
+type configFileTransform func(path string, values map[string]any) (bool, error)
+
+// preparedConfigSources holds the configuration sources to load in memory, after clio-specific processing has been
+// applied
+type preparedConfigSources struct {
+	fangsCfg fangs.Config
+	warnings []configWarning
+
+	// files are the values of each configuration file to load, in precedence order
+	files []configFileValues
+
+	// env are all application environment variables to load
+	env map[string]string
+
+	// overrides are the values set with --set, by lowercase key
+	overrides map[string]string
+
+	// dotEnv are the variables read from dotenv files, as read, for environment variable references
+	dotEnv map[string]dotEnvValue
+
+	// flagValues are the transformed values of flags which have been set
+	flagValues map[*pflag.Flag]string
+
+	// interpolated are all values interpolated into configuration values from environment variables
+	interpolated []string
+}
+
+// prepareConfigSources reads the configuration files, including all included files, and environment variables that
+// will be loaded into the configs, then applies all configured transforms to these and any flags in memory
+func (a *application) prepareConfigSources(cmd *cobra.Command, fangsCfg fangs.Config, cfgs ...any) (*preparedConfigSources, error) {
+	p := &preparedConfigSources{fangsCfg: fangsCfg, env: applicationEnv(fangsCfg.AppName), overrides: map[string]string{}}
+
+	// dotenv files and overrides are applied first, so any other transforms also apply to these values
+	err := errors.Join(
+		p.loadDotEnvFiles(a.setupConfig.dotEnvFiles),
+		p.applyConfigOverrides(a.configOverrides, appendUnique(allCommandConfigs(a), cfgs...)...),
+	)
+	if err == nil {
+		p.files, err = a.readConfigSources(fangsCfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	transforms := a.configValueTransforms(p)
+	deprecatedKeys := a.setupConfig.deprecatedKeys
+	migrations := a.setupConfig.configMigrations
+
+	var fileTransforms []configFileTransform
+	if len(migrations) > 0 {
+		// migrations are applied first, so all other transforms apply to the latest configuration version
+		fileTransforms = append(fileTransforms, migrationTransform(migrations))
+	}
+	if len(deprecatedKeys) > 0 {
+		fileTransforms = append(fileTransforms, p.deprecatedKeyTransform(deprecatedKeys))
+	}
+	if len(transforms) > 0 {
+		fileTransforms = append(fileTransforms, func(_ string, values map[string]any) (bool, error) {
+			return transformConfigValues(values, transforms)
+		})
+	}
+
+	if keys := configRelativePathKeys(fangsCfg.TagName, appendUnique(allCommandConfigs(a), cfgs...)...); len(keys) > 0 {
+		fileTransforms = append(fileTransforms, p.configRelativePathTransform(keys))
+	}
+
+	var unknown []string
+	var known knownConfigKeys
+	if a.setupConfig.strictConfig != strictConfigDisabled {
+		// unknown keys are checked last, once all keys have been migrated
+		known = a.knownConfigKeys(cfgs...)
+		fileTransforms = append(fileTransforms, func(path string, values map[string]any) (bool, error) {
+			unknown = append(unknown, known.unknownKeyMessages(values, fangsCfg.ProfileKey, "file "+path)...)
+			return false, nil
+		})
+	}
+
+	p.aliasDeprecatedEnv(deprecatedKeys)
+	err = errors.Join(
+		p.transformFiles(fileTransforms),
+		p.transformEnv(transforms),
+		p.transformOverrides(transforms),
+		p.transformFlags(cmd, transforms),
+	)
+
+	if a.setupConfig.strictConfig != strictConfigDisabled {
+		unknown = append(unknown, known.unknownEnvVarMessages(fangsCfg.AppName, p.env)...)
+		err = errors.Join(err, p.reportUnknownKeys(a.setupConfig.strictConfig, unknown))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// postLoad finishes loading the configs from the prepared sources, registering sensitive values with the redact store
+// before any configuration is logged
+func (p *preparedConfigSources) postLoad(store redact.Store, cfgs ...any) {
+	redactSensitiveValues(store, p.fangsCfg.TagName, cfgs...)
+	redactInterpolatedValues(store, p.fangsCfg.TagName, p.interpolated, cfgs...)
+}
+
+// reportUnknownKeys returns an error for all unknown configuration keys in strict mode, otherwise records a warning
+// for each unknown key
+func (p *preparedConfigSources) reportUnknownKeys(mode strictConfigMode, unknown []string) error {
+	if len(unknown) == 0 {
+		return nil
+	}
+	if mode == strictConfigError {
+		return fmt.Errorf("unknown configuration keys:\n  - %s", strings.Join(unknown, "\n  - "))
+	}
+	for _, msg := range unknown {
+		p.warnings = append(p.warnings, configWarning{id: msg, message: msg})
+	}
+	return nil
+}
+
+func (a *application) configValueTransforms(p *preparedConfigSources) []configValueTransform {
+	var transforms []configValueTransform
+	if a.setupConfig.interpolation {
+		// interpolation is applied first, so interpolated values may be secret references
+		transforms = append(transforms, p.interpolationTransform())
+	}
+	if a.setupConfig.secretReferences {
+		transforms = append(transforms, secretReferenceTransform(a.state.RedactStore, dotEnvLookup(p.dotEnv)))
+	}
+	return transforms
+}
+
+// transformFiles applies the transforms to the values of each configuration file in memory
+func (p *preparedConfigSources) transformFiles(transforms []configFileTransform) error {
+	for _, f := range p.files {
+		for _, transform := range transforms {
+			if _, err := transform(f.path, f.values); err != nil {
+				return fmt.Errorf("invalid configuration file %s: %w", f.path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applicationEnv returns all environment variables with the application prefix
+func applicationEnv(appName string) map[string]string {
+	prefix := envVarName(appName, "")
+	env := map[string]string{}
+	for _, e := range os.Environ() {
+		name, value, _ := strings.Cut(e, "=")
+		if strings.HasPrefix(name, prefix) {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// transformEnv applies the transforms to all application environment variables
+func (p *preparedConfigSources) transformEnv(transforms []configValueTransform) error {
+	if p.fangsCfg.AppName == "" || len(transforms) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, name := range sortedKeys(p.env) {
+		transformed, _, err := applyValueTransforms(p.env[name], transforms)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid environment variable %s: %w", name, err))
+			continue
+		}
+		p.env[name] = transformed
+	}
+	return errors.Join(errs...)
+}
+
+// transformFlags applies the transforms to the values of all string flags which have been set, the flags themselves are
+// never modified
+func (p *preparedConfigSources) transformFlags(cmd *cobra.Command, transforms []configValueTransform) error {
+	if cmd == nil || len(transforms) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, f := range changedFlags(cmd) {
+		if f.Value.Type() != "string" {
+			continue
+		}
+
+		transformed, changed, err := applyValueTransforms(f.Value.String(), transforms)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid flag --%s: %w", f.Name, err))
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if p.flagValues == nil {
+			p.flagValues = map[*pflag.Flag]string{}
+		}
+		p.flagValues[f] = transformed
+	}
+	return errors.Join(errs...)
+}
+
+// transformConfigValues applies the transforms to every string value in the configuration, returning true if any
+// values were changed
+func transformConfigValues(values map[string]any, transforms []configValueTransform) (bool, error) {
+	changed := false
+	var errs []error
+	var visit func(v any) any
+	visit = func(v any) any {
+		switch v := v.(type) {
+		case string:
+			transformed, modified, err := applyValueTransforms(v, transforms)
+			if err != nil {
+				errs = append(errs, err)
+				return v
+			}
+			changed = changed || modified
+			return transformed
+		case map[string]any:
+			for k, value := range v {
+				v[k] = visit(value)
+			}
+		case []any:
+			for i, value := range v {
+				v[i] = visit(value)
+			}
+		}
+		return v
+	}
+	visit(values)
+	return changed, errors.Join(errs...)
+}
+
+func applyValueTransforms(value string, transforms []configValueTransform) (string, bool, error) {
+	changed := false
+	for _, transform := range transforms {
+		transformed, modified, err := transform(value)
+		if err != nil {
+			return value, false, err
+		}
+		value = transformed
+		changed = changed || modified
+	}
+	return value, changed, nil
+}
@@ -0,0 +1,72 @@
+package clio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// stdinConfigFile is the configuration file name used to read configuration from stdin, e.g. `-c -`
+	stdinConfigFile = "-"
+
+	// stdinSourceName is the name shown for configuration read from stdin
+	stdinSourceName = "stdin"
+)
+
+var errStdinConfigFile = errors.New("configuration from stdin (-c -) is not supported by this command")
+
+// stdinConfigFormats are the formats configuration read from stdin is detected as, in order
+var stdinConfigFormats = []string{"json", "yaml", "toml"}
+
+// stdinConfigValues returns the configuration read from stdin, which is only read the first time it is needed
+func (a *application) stdinConfigValues() (map[string]any, error) {
+	if a.stdinConfig != nil {
+		return a.stdinConfig, nil
+	}
+
+	contents, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration from stdin: %w", err)
+	}
+
+	values, err := parseConfigContents(contents)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration from stdin: %w", err)
+	}
+
+	// there is no including file, so relative includes are relative to the working directory
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	includes, err := configIncludeValues(values, cwd, stdinSourceName)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range includeKeys {
+		delete(values, key)
+	}
+	if len(includes) > 0 {
+		values[includeKeys[0]] = includes
+	}
+
+	a.stdinConfig = values
+	return values, nil
+}
+
+// parseConfigContents reads the configuration document, detecting the format from the contents
+func parseConfigContents(contents []byte) (map[string]any, error) {
+	for _, format := range stdinConfigFormats {
+		v := viper.New()
+		v.SetConfigType(format)
+		if err := v.ReadConfig(bytes.NewReader(contents)); err == nil {
+			return v.AllSettings(), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported configuration format, expected one of: %v", stdinConfigFormats)
+}
@@ -0,0 +1,138 @@
+package clio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseConfigContents(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     map[string]any
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name:     "empty",
+			contents: "",
+			want:     map[string]any{},
+		},
+		{
+			name:     "json",
+			contents: "{\n\t\"name\": \"the-name\",\n\t\"log\": {\"level\": \"debug\"}\n}",
+			want:     map[string]any{"name": "the-name", "log": map[string]any{"level": "debug"}},
+		},
+		{
+			name:     "yaml",
+			contents: "name: the-name\nlog:\n  level: debug\n",
+			want:     map[string]any{"name": "the-name", "log": map[string]any{"level": "debug"}},
+		},
+		{
+			name:     "toml",
+			contents: "name = \"the-name\"\n\n[log]\nlevel = \"debug\"\n",
+			want:     map[string]any{"name": "the-name", "log": map[string]any{"level": "debug"}},
+		},
+		{
+			name:     "unsupported",
+			contents: "[not valid",
+			wantErr:  require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := parseConfigContents([]byte(tt.contents))
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// withStdin replaces stdin with the given contents for the duration of the test
+func withStdin(t *testing.T, contents string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "stdin")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	original := os.Stdin
+	os.Stdin = f
+	t.Cleanup(func() {
+		os.Stdin = original
+		_ = f.Close()
+	})
+}
+
+func newStdinApp(t *testing.T) *application {
+	t.Helper()
+
+	app, _ := newConfigTestApp(t, NewSetupConfig(Identification{Name: "my-app"}), map[string]string{
+		"base.yaml":  "count: 3\n",
+		"other.yaml": "name: other-name\ncount: 5\n",
+	}, stdinConfigFile, "other.yaml")
+
+	withStdin(t, `{"name": "stdin-name", "include": "base.yaml"}`)
+	return app
+}
+
+func Test_StdinConfig(t *testing.T) {
+	app := newStdinApp(t)
+
+	// stdin is only read once, so all loads use the same configuration
+	for i := 0; i < 2; i++ {
+		opts := &configTestOptions{}
+		_, err := app.loadConfigs(&cobra.Command{}, opts)
+		require.NoError(t, err)
+
+		assert.Equal(t, "stdin-name", opts.Name)
+		// included files relative to the working directory take precedence over later files
+		assert.Equal(t, 3, opts.Count)
+	}
+}
+
+func Test_ConfigExplainCommand_stdin(t *testing.T) {
+	app := newStdinApp(t)
+	_ = app.SetupCommand(&cobra.Command{}, &configTestOptions{})
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeExplainSubcommand(true))
+	explainCmd, _, err := configCmd.Find([]string{"explain"})
+	require.NoError(t, err)
+
+	stdout, _ := captureStd(func() {
+		require.NoError(t, explainCmd.RunE(explainCmd, nil))
+	})
+
+	rows := explainRows(stdout)
+	assert.Equal(t, []string{"name", "'stdin-name'", "file stdin"}, rows["name"])
+	assert.Equal(t, []string{"count", "3", "file " + filepath.Join(app.setupConfig.FangsConfig.Files[1], "..", "base.yaml")}, rows["count"])
+
+	locationsCmd, _, err := configCmd.Find([]string{"locations"})
+	require.NoError(t, err)
+
+	stdout, _ = captureStd(func() {
+		require.NoError(t, locationsCmd.RunE(locationsCmd, nil))
+	})
+	assert.Contains(t, stdout, "stdin\n"+app.setupConfig.FangsConfig.Files[1]+"\n")
+}
+
+func Test_StdinConfig_unsupportedCommand(t *testing.T) {
+	cfg := NewSetupConfig(Identification{Name: "my-app"})
+	cfg.FangsConfig.Files = []string{stdinConfigFile}
+
+	// commands which update the configuration file cannot use configuration from stdin
+	_, err := activeConfigFile(cfg.FangsConfig)
+	require.ErrorIs(t, err, errStdinConfigFile)
+}
@@ -0,0 +1,207 @@
+package clio
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type strictConfigMode int
+
+const (
+	strictConfigDisabled strictConfigMode = iota
+	strictConfigWarn
+	strictConfigError
+)
+
+// knownConfigKeys describes all configuration keys which map onto configuration fields of any command
+type knownConfigKeys struct {
+	// fields are the keys of all configuration fields, values nested below these keys (e.g. map entries) are not checked
+	fields map[string]struct{}
+
+	// sections are the keys of all nested configuration structs
+	sections map[string]struct{}
+
+	// reserved are top-level keys which are not configuration fields but are used while loading configuration
+	reserved []string
+
+	// envVars are the names of all environment variables which are used while loading configuration
+	envVars map[string]struct{}
+}
+
+// knownConfigKeys returns the keys for all configs registered with the application and the given configs, keys used by
+// any command are allowed since configuration files are shared between all commands
+func (a *application) knownConfigKeys(cfgs ...any) knownConfigKeys {
+	fangsCfg := a.setupConfig.FangsConfig
+	k := knownConfigKeys{
+		fields:   map[string]struct{}{},
+		sections: map[string]struct{}{},
+		reserved: append([]string{configVersionKey}, includeKeys...),
+		envVars: map[string]struct{}{
+			envVarName(fangsCfg.AppName, "CONFIG"):  {},
+			envVarName(fangsCfg.AppName, "PROFILE"): {},
+			runIDEnvVar(fangsCfg.AppName):           {},
+		},
+	}
+
+	for _, cfg := range appendUnique(allCommandConfigs(a), cfgs...) {
+		visitConfigFields(fangsCfg.TagName, cfg, func(path []string, _ reflect.StructField, _ reflect.Value) {
+			k.fields[strings.ToLower(strings.Join(path, "."))] = struct{}{}
+			k.envVars[envVarName(fangsCfg.AppName, path...)] = struct{}{}
+			for i := 1; i < len(path); i++ {
+				k.sections[strings.ToLower(strings.Join(path[:i], "."))] = struct{}{}
+			}
+		})
+	}
+
+	for _, key := range a.setupConfig.deprecatedKeys {
+		k.envVars[envVarName(fangsCfg.AppName, configKeyPath(key.Key)...)] = struct{}{}
+	}
+
+	return k
+}
+
+// unknownKeys returns all keys in the raw configuration values which do not map onto a configuration field
+func (k knownConfigKeys) unknownKeys(values map[string]any, profileKey string) []string {
+	var unknown []string
+	for _, key := range sortedKeys(values) {
+		switch {
+		case slices.Contains(k.reserved, key):
+			continue
+		case profileKey != "" && key == strings.ToLower(profileKey):
+			profiles, ok := values[key].(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, name := range sortedKeys(profiles) {
+				if profile, ok := profiles[name].(map[string]any); ok {
+					for _, u := range k.unknownSectionKeys(profile, nil) {
+						unknown = append(unknown, strings.Join([]string{key, name, u}, "."))
+					}
+				}
+			}
+			continue
+		}
+		unknown = append(unknown, k.unknownSectionKeys(map[string]any{key: values[key]}, nil)...)
+	}
+	return unknown
+}
+
+func (k knownConfigKeys) unknownSectionKeys(values map[string]any, path []string) []string {
+	var unknown []string
+	for _, key := range sortedKeys(values) {
+		keyPath := append(slices.Clone(path), key)
+		name := strings.Join(keyPath, ".")
+		if _, ok := k.fields[name]; ok {
+			continue
+		}
+		if _, ok := k.sections[name]; ok {
+			if section, ok := values[key].(map[string]any); ok {
+				unknown = append(unknown, k.unknownSectionKeys(section, keyPath)...)
+			}
+			continue
+		}
+		unknown = append(unknown, name)
+	}
+	return unknown
+}
+
+// unknownEnvVars returns all application environment variables which are not used for configuration
+func (k knownConfigKeys) unknownEnvVars(appName string, env map[string]string) []string {
+	if appName == "" {
+		return nil
+	}
+
+	var unknown []string
+	for name := range env {
+		if _, ok := k.envVars[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
+
+// suggestKey returns the known key most similar to the unknown key, or an empty string if none are similar enough
+func suggestKey(unknown string, known []string) string {
+	// allow roughly one edit for every three characters
+	best, bestDistance := "", len(unknown)/3+1
+	for _, candidate := range known {
+		if d := editDistance(strings.ToLower(unknown), strings.ToLower(candidate)); d < bestDistance || (d == bestDistance && best != "" && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the optimal string alignment distance between the strings: the number of insertions, deletions,
+// substitutions, and transpositions of adjacent characters needed to change one string into the other
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// unknownKeyMessages describes all unknown keys in the raw configuration values, suggesting similar known keys
+func (k knownConfigKeys) unknownKeyMessages(values map[string]any, profileKey, source string) []string {
+	known := sortedKeys(k.fields)
+	var messages []string
+	for _, key := range k.unknownKeys(values, profileKey) {
+		msg := fmt.Sprintf("unknown configuration key %q (%s)", key, source)
+
+		// keys within profiles are suggested relative to the profile
+		prefix, name := "", key
+		if parts := strings.SplitN(key, ".", 3); profileKey != "" && len(parts) == 3 && parts[0] == strings.ToLower(profileKey) {
+			prefix, name = parts[0]+"."+parts[1]+".", parts[2]
+		}
+		if suggestion := suggestKey(name, known); suggestion != "" {
+			msg += fmt.Sprintf(", did you mean %q?", prefix+suggestion)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// unknownEnvVarMessages describes all unknown application environment variables, suggesting similar known variables
+func (k knownConfigKeys) unknownEnvVarMessages(appName string, env map[string]string) []string {
+	known := sortedKeys(k.envVars)
+	var messages []string
+	for _, env := range k.unknownEnvVars(appName, env) {
+		msg := fmt.Sprintf("unknown configuration environment variable %q", env)
+		if suggestion := suggestKey(env, known); suggestion != "" {
+			msg += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,128 @@
+package clio
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "level", b: "level", want: 0},
+		{a: "level", b: "levle", want: 1},
+		{a: "level", b: "levels", want: 1},
+		{a: "level", b: "lvl", want: 2},
+		{a: "", b: "abc", want: 3},
+		{a: "file", b: "quiet", want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"->"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, editDistance(tt.a, tt.b))
+			assert.Equal(t, tt.want, editDistance(tt.b, tt.a))
+		})
+	}
+}
+
+func Test_suggestKey(t *testing.T) {
+	known := []string{"log.file", "log.level", "log.quiet", "name"}
+	tests := []struct {
+		unknown string
+		want    string
+	}{
+		{unknown: "log.levle", want: "log.level"},
+		{unknown: "LOG.LEVEL", want: "log.level"},
+		{unknown: "nmae", want: "name"},
+		{unknown: "log.fil", want: "log.file"},
+		{unknown: "registry", want: ""},
+		{unknown: "x", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unknown, func(t *testing.T) {
+			assert.Equal(t, tt.want, suggestKey(tt.unknown, known))
+		})
+	}
+}
+
+func newStrictApp(t *testing.T, cfg *SetupConfig, contents string) (*application, string) {
+	t.Helper()
+	return newConfigTestApp(t, cfg, map[string]string{".my-app.yaml": contents}, ".my-app.yaml")
+}
+
+const strictConfigContents = `
+config-version: 1
+name: the-name
+labels:
+  any-key: value
+log:
+  levle: debug
+profiles:
+  ci:
+    nmae: ci-name
+extra: value
+`
+
+func Test_StrictConfig(t *testing.T) {
+	t.Setenv("MY_APP_NAEM", "env-name")
+
+	app, configFile := newStrictApp(t, NewSetupConfig(Identification{Name: "my-app"}).WithStrictConfig(), strictConfigContents)
+
+	_, err := app.loadConfigs(&cobra.Command{}, &configTestOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown configuration key "log.levle" (file `+configFile+`), did you mean "log.level"?`)
+	assert.Contains(t, err.Error(), `unknown configuration key "profiles.ci.nmae" (file `+configFile+`), did you mean "profiles.ci.name"?`)
+	assert.Contains(t, err.Error(), `unknown configuration key "extra" (file `+configFile+`)`+"\n")
+	assert.Contains(t, err.Error(), `unknown configuration environment variable "MY_APP_NAEM", did you mean "MY_APP_NAME"?`)
+	assert.NotContains(t, err.Error(), "config-version")
+	assert.NotContains(t, err.Error(), "any-key")
+}
+
+func Test_StrictConfigWarnings(t *testing.T) {
+	app, _ := newStrictApp(t, NewSetupConfig(Identification{Name: "my-app"}).WithStrictConfigWarnings(), strictConfigContents)
+
+	opts := &configTestOptions{}
+	_, err := app.loadConfigs(&cobra.Command{}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "the-name", opts.Name)
+	// keys of map fields are never unknown
+	assert.Equal(t, map[string]string{"any-key": "value"}, opts.Labels)
+
+	messages := app.configWarningMessages()
+	require.Len(t, messages, 3)
+	assert.Contains(t, messages[0], `unknown configuration key "extra"`)
+	assert.Contains(t, messages[1], `unknown configuration key "log.levle"`)
+	assert.Contains(t, messages[2], `unknown configuration key "profiles.ci.nmae"`)
+}
+
+func Test_StrictConfig_otherCommandKeys(t *testing.T) {
+	app, _ := newStrictApp(t, NewSetupConfig(Identification{Name: "my-app"}).WithStrictConfig(), "name: the-name\nother: value\n")
+
+	type otherOptions struct {
+		Other string `mapstructure:"other"`
+	}
+	_ = app.SetupCommand(&cobra.Command{Use: "other"}, &otherOptions{})
+
+	// keys for configs of other commands are known
+	_, err := app.loadConfigs(&cobra.Command{}, &configTestOptions{})
+	require.NoError(t, err)
+}
+
+func Test_ConfigValidateCommand_strictConfig(t *testing.T) {
+	app, configFile := newStrictApp(t, NewSetupConfig(Identification{Name: "my-app"}).WithStrictConfigWarnings(), "name: the-name\nnmae: typo\n")
+	_ = app.SetupCommand(&cobra.Command{}, &configTestOptions{})
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeValidateSubcommand(true))
+	validateCmd, _, err := configCmd.Find([]string{"validate"})
+	require.NoError(t, err)
+
+	stdout, stderr := captureStd(func() {
+		require.NoError(t, validateCmd.RunE(validateCmd, nil))
+	})
+	assert.Equal(t, "my-app configuration is valid\n", stdout)
+	assert.Equal(t, `warning: unknown configuration key "nmae" (file `+configFile+`), did you mean "name"?`+"\n", stderr)
+}
@@ -0,0 +1,31 @@
+package clio
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func configValidateCommand(internalApp *application) *cobra.Command {
+	id := internalApp.ID()
+	return &cobra.Command{
+		Use:   "validate",
+		Short: fmt.Sprintf("validate the %s configuration, reporting any errors or deprecated configuration keys", id.Name),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := loadAllConfigs(cmd, internalApp, internalApp.setupConfig.FangsConfig, allCommandConfigs(internalApp))
+
+			for _, warning := range internalApp.configWarningMessages() {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(os.Stdout, "%s configuration is valid\n", id.Name)
+			return err
+		},
+	}
+}
@@ -0,0 +1,272 @@
+package clio
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validation rules for configuration fields are set in the clio struct tag alongside any other field options, e.g.
+// `clio:"required,oneof=json text"`. Available rules:
+//   - required: the value must not be empty (or zero)
+//   - oneof=a b c: the value must be one of the space-separated values
+//   - min=N, max=N: the number (or duration) must be within the bound, for strings, slices, and maps the length must be
+//   - regex=PATTERN: the value must match the regular expression, this must be the last rule as the pattern may contain commas
+//   - file-exists: the value must be the path of an existing file
+//
+// oneof, regex, and file-exists are only checked for non-empty values and are checked for each element of slices.
+// Rules are not supported on struct fields, the fields of the nested struct are validated individually.
+
+// clioFieldOptions are the clio struct tag options which are not validation rules
+var clioFieldOptions = []string{sensitiveTagOption, pathTagOption, configRelativeTagOption}
+
+// configViolation describes a single configuration value which failed validation
+type configViolation struct {
+	key     string
+	message string
+}
+
+func (v configViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.key, v.message)
+}
+
+// validateConfigs checks all configuration fields against their validation rules, returning a single error describing
+// every violation found
+func validateConfigs(tagName string, cfgs ...any) error {
+	var violations []configViolation
+	seen := map[string]bool{}
+	for _, cfg := range cfgs {
+		for _, violation := range structFieldRuleViolations(tagName, reflect.TypeOf(cfg), nil, nil) {
+			if !seen[violation.key] {
+				seen[violation.key] = true
+				violations = append(violations, violation)
+			}
+		}
+
+		visitConfigFields(tagName, cfg, func(path []string, f reflect.StructField, v reflect.Value) {
+			tag := f.Tag.Get(clioTagName)
+			if len(parseValidationRules(tag)) == 0 {
+				return
+			}
+			key := strings.Join(path, ".")
+			if seen[key] {
+				// the same key may be used by multiple commands, but is loaded from the same source
+				return
+			}
+			seen[key] = true
+
+			for _, msg := range validateValue(tag, v) {
+				violations = append(violations, configViolation{key: key, message: msg})
+			}
+		})
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	sb.WriteString("configuration validation failed:")
+	for _, v := range violations {
+		sb.WriteString("\n  - " + v.String())
+	}
+	return errors.New(sb.String())
+}
+
+// structFieldRuleViolations returns a violation for each struct field with validation rules, which are not supported
+func structFieldRuleViolations(tagName string, t reflect.Type, path []string, parents []reflect.Type) []configViolation {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || slices.Contains(parents, t) {
+		return nil
+	}
+	parents = append(parents, t)
+
+	var violations []configViolation
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, squash, skip := configFieldName(tagName, f)
+		if skip {
+			continue
+		}
+
+		fieldPath := slices.Clone(path)
+		if !squash {
+			fieldPath = append(fieldPath, name)
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Struct {
+			continue
+		}
+		if len(parseValidationRules(f.Tag.Get(clioTagName))) > 0 {
+			violations = append(violations, configViolation{
+				key:     strings.Join(fieldPath, "."),
+				message: "validation rules are not supported on struct fields",
+			})
+		}
+		violations = append(violations, structFieldRuleViolations(tagName, ft, fieldPath, parents)...)
+	}
+	return violations
+}
+
+// validateValue returns a message for each validation rule in the tag the value does not satisfy
+func validateValue(tag string, v reflect.Value) []string {
+	v = derefValue(v)
+
+	var messages []string
+	for _, rule := range parseValidationRules(tag) {
+		if msg := rule.check(v); msg != "" {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+type validationRule struct {
+	name  string
+	param string
+}
+
+// parseValidationRules splits the comma-separated rules, skipping any other clio field options. Any regex rule consumes
+// the remainder of the tag.
+func parseValidationRules(tag string) []validationRule {
+	var rules []validationRule
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, "regex=") {
+			part, tag = tag, ""
+		} else {
+			part, tag, _ = strings.Cut(tag, ",")
+		}
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name != "" && !slices.Contains(clioFieldOptions, name) {
+			rules = append(rules, validationRule{name: name, param: param})
+		}
+	}
+	return rules
+}
+
+func (r validationRule) check(v reflect.Value) string {
+	switch r.name {
+	case "required":
+		if !v.IsValid() || v.IsZero() || (hasLength(v) && v.Len() == 0) {
+			return "is required"
+		}
+		return ""
+	case "min", "max":
+		return r.checkBound(v)
+	case "oneof":
+		allowed := strings.Fields(r.param)
+		return checkEach(v, func(s string) string {
+			for _, a := range allowed {
+				if s == a {
+					return ""
+				}
+			}
+			return fmt.Sprintf("must be one of [%s], got %q", strings.Join(allowed, ", "), s)
+		})
+	case "regex":
+		pattern, err := regexp.Compile(r.param)
+		if err != nil {
+			return fmt.Sprintf("invalid regex rule %q: %v", r.param, err)
+		}
+		return checkEach(v, func(s string) string {
+			if pattern.MatchString(s) {
+				return ""
+			}
+			return fmt.Sprintf("must match %q, got %q", r.param, s)
+		})
+	case "file-exists":
+		return checkEach(v, func(s string) string {
+			if fileExists(s) {
+				return ""
+			}
+			return fmt.Sprintf("file does not exist: %s", s)
+		})
+	}
+	return fmt.Sprintf("unknown validation rule %q", r.name)
+}
+
+// checkBound checks the min or max rule against numbers and durations, or the length of strings, slices, and maps
+func (r validationRule) checkBound(v reflect.Value) string {
+	if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return ""
+	}
+
+	var actual, bound float64
+	subject := "must be"
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(r.param)
+		if err != nil {
+			return fmt.Sprintf("invalid %s rule %q: %v", r.name, r.param, err)
+		}
+		actual, bound = float64(v.Int()), float64(d)
+	case hasLength(v):
+		subject = "length must be"
+		actual = float64(v.Len())
+	case v.CanInt():
+		actual = float64(v.Int())
+	case v.CanUint():
+		actual = float64(v.Uint())
+	case v.CanFloat():
+		actual = v.Float()
+	default:
+		return fmt.Sprintf("%s rule is not supported for type %s", r.name, v.Type())
+	}
+
+	if v.Type() != reflect.TypeOf(time.Duration(0)) {
+		b, err := strconv.ParseFloat(r.param, 64)
+		if err != nil {
+			return fmt.Sprintf("invalid %s rule %q: %v", r.name, r.param, err)
+		}
+		bound = b
+	}
+
+	switch {
+	case r.name == "min" && actual < bound:
+		return fmt.Sprintf("%s at least %s", subject, r.param)
+	case r.name == "max" && actual > bound:
+		return fmt.Sprintf("%s at most %s", subject, r.param)
+	}
+	return ""
+}
+
+// checkEach applies the check to the string representation of the value, or of each element for slices, skipping
+// empty values
+func checkEach(v reflect.Value, check func(string) string) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		var messages []string
+		for i := 0; i < v.Len(); i++ {
+			if msg := checkEach(derefValue(v.Index(i)), check); msg != "" {
+				messages = append(messages, msg)
+			}
+		}
+		return strings.Join(messages, "; ")
+	}
+	if v.IsZero() {
+		return ""
+	}
+	return check(fmt.Sprintf("%v", v.Interface()))
+}
+
+func hasLength(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,160 @@
+package clio
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseValidationRules(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []validationRule
+	}{
+		{tag: "", want: nil},
+		{tag: "required", want: []validationRule{{name: "required"}}},
+		{
+			tag:  "required, oneof=a b,min=1",
+			want: []validationRule{{name: "required"}, {name: "oneof", param: "a b"}, {name: "min", param: "1"}},
+		},
+		{
+			tag:  "sensitive,required,path",
+			want: []validationRule{{name: "required"}},
+		},
+		{tag: "config-relative", want: nil},
+		{
+			tag:  "min=1,regex=^[a-z]{1,3}$",
+			want: []validationRule{{name: "min", param: "1"}, {name: "regex", param: "^[a-z]{1,3}$"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseValidationRules(tt.tag))
+		})
+	}
+}
+
+func Test_validateValue(t *testing.T) {
+	existing := filepath.Join(t.TempDir(), "exists.txt")
+	require.NoError(t, os.WriteFile(existing, nil, 0o600))
+
+	tests := []struct {
+		name  string
+		tag   string
+		value any
+		want  []string
+	}{
+		{name: "required string", tag: "required", value: "", want: []string{"is required"}},
+		{name: "required set", tag: "required", value: "value"},
+		{name: "required slice", tag: "required", value: []string{}, want: []string{"is required"}},
+		{name: "required nil pointer", tag: "required", value: (*int)(nil), want: []string{"is required"}},
+		{name: "oneof", tag: "oneof=json text", value: "yaml", want: []string{`must be one of [json, text], got "yaml"`}},
+		{name: "oneof valid", tag: "oneof=json text", value: "text"},
+		{name: "oneof empty", tag: "oneof=json text", value: ""},
+		{name: "oneof slice", tag: "oneof=a b", value: []string{"a", "c"}, want: []string{`must be one of [a, b], got "c"`}},
+		{name: "min int", tag: "min=1", value: 0, want: []string{"must be at least 1"}},
+		{name: "max int", tag: "max=10", value: 11, want: []string{"must be at most 10"}},
+		{name: "min max valid", tag: "min=1,max=10", value: 5},
+		{name: "min float", tag: "min=0.5", value: 0.25, want: []string{"must be at least 0.5"}},
+		{name: "max string", tag: "max=3", value: "abcd", want: []string{"length must be at most 3"}},
+		{name: "min slice", tag: "min=2", value: []string{"a"}, want: []string{"length must be at least 2"}},
+		{name: "min duration", tag: "min=1s", value: 500 * time.Millisecond, want: []string{"must be at least 1s"}},
+		{name: "max duration", tag: "max=1m", value: 30 * time.Second},
+		{name: "regex", tag: "regex=^v[0-9]+$", value: "1", want: []string{`must match "^v[0-9]+$", got "1"`}},
+		{name: "regex valid", tag: "regex=^v[0-9]{1,2}$", value: "v12"},
+		{name: "file exists", tag: "file-exists", value: existing},
+		{name: "file missing", tag: "file-exists", value: "/does/not/exist", want: []string{"file does not exist: /does/not/exist"}},
+		{name: "multiple", tag: "required,min=3", value: "", want: []string{"is required", "length must be at least 3"}},
+		{name: "invalid bound", tag: "min=one", value: 1, want: []string{`invalid min rule "one": strconv.ParseFloat: parsing "one": invalid syntax`}},
+		{name: "unknown rule", tag: "email", value: "x", want: []string{`unknown validation rule "email"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, validateValue(tt.tag, reflect.ValueOf(tt.value)))
+		})
+	}
+}
+
+type validatedOptions struct {
+	Name     string `mapstructure:"name" clio:"required"`
+	Other    string `mapstructure:"other" validate:"required"`
+	Format   string `mapstructure:"format" clio:"oneof=json text"`
+	Registry struct {
+		Retries int    `mapstructure:"retries" clio:"min=0,max=5"`
+		URL     string `mapstructure:"url" clio:"regex=^https?://"`
+	} `mapstructure:"registry"`
+}
+
+func newValidatedApp(t *testing.T, contents string) Application {
+	t.Helper()
+
+	configFile := filepath.Join(t.TempDir(), ".my-app.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(contents), 0o600))
+
+	cfg := NewSetupConfig(Identification{Name: "my-app"})
+	cfg.FangsConfig.Files = []string{configFile}
+	return New(*cfg)
+}
+
+const invalidValidatedConfig = `format: yaml
+registry:
+  retries: 10
+  url: ftp://example.com
+`
+
+func Test_ConfigValidation(t *testing.T) {
+	app := newValidatedApp(t, invalidValidatedConfig)
+
+	_, err := app.(*application).loadConfigs(&cobra.Command{}, &validatedOptions{})
+	require.Error(t, err)
+	assert.Equal(t, `invalid application config: configuration validation failed:
+  - name: is required
+  - format: must be one of [json, text], got "yaml"
+  - registry.retries: must be at most 5
+  - registry.url: must match "^https?://", got "ftp://example.com"`, err.Error())
+
+	app = newValidatedApp(t, "name: the-name\nformat: json\n")
+	_, err = app.(*application).loadConfigs(&cobra.Command{}, &validatedOptions{})
+	require.NoError(t, err)
+}
+
+func Test_ConfigValidateCommand_validation(t *testing.T) {
+	app := newValidatedApp(t, invalidValidatedConfig)
+	_ = app.SetupCommand(&cobra.Command{}, &validatedOptions{})
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeValidateSubcommand(true))
+	validateCmd, _, err := configCmd.Find([]string{"validate"})
+	require.NoError(t, err)
+
+	err = validateCmd.RunE(validateCmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "  - name: is required\n")
+	assert.Contains(t, err.Error(), "  - registry.url: must match")
+}
+
+type structRuleOptions struct {
+	Registry struct {
+		URL string `mapstructure:"url"`
+	} `mapstructure:"registry" clio:"required"`
+	Nested *struct {
+		Inner struct {
+			Value string `mapstructure:"value"`
+		} `mapstructure:"inner" clio:"required"`
+	} `mapstructure:"nested"`
+}
+
+func Test_ConfigValidation_structFields(t *testing.T) {
+	app := newValidatedApp(t, "")
+
+	_, err := app.(*application).loadConfigs(&cobra.Command{}, &structRuleOptions{})
+	require.Error(t, err)
+	assert.Equal(t, `invalid application config: configuration validation failed:
+  - registry: validation rules are not supported on struct fields
+  - nested.inner: validation rules are not supported on struct fields`, err.Error())
+}
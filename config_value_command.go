@@ -0,0 +1,262 @@
+package clio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+func configGetCommand(internalApp *application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get KEY",
+		Short: fmt.Sprintf("show the resolved value of a single %s configuration key (e.g. log.level)", internalApp.ID().Name),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fangsCfg := internalApp.setupConfig.FangsConfig
+			allConfigs := allCommandConfigs(internalApp)
+
+			if err := loadAllConfigs(cmd, internalApp, fangsCfg, allConfigs); err != nil {
+				return err
+			}
+
+			v, ok := lookupConfigValue(fangsCfg.TagName, args[0], allConfigs...)
+			if !ok {
+				return fmt.Errorf("unknown configuration key: %q", args[0])
+			}
+
+			value, err := formatConfigValue(v)
+			if err != nil {
+				return fmt.Errorf("unable to show configuration value: %w", err)
+			}
+
+			if internalApp.state.RedactStore != nil {
+				value = internalApp.state.RedactStore.RedactString(value)
+			}
+
+			_, err = os.Stdout.WriteString(value + "\n")
+			return err
+		},
+	}
+}
+
+func configSetCommand(internalApp *application) *cobra.Command {
+	id := internalApp.ID()
+	return &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: fmt.Sprintf("set the value of a single %s configuration key in the active configuration file", id.Name),
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+			fangsCfg := internalApp.setupConfig.FangsConfig
+
+			target, ok := lookupConfigValue(fangsCfg.TagName, key, allCommandConfigs(internalApp)...)
+			if !ok {
+				return fmt.Errorf("unknown configuration key: %q", key)
+			}
+
+			node, err := yamlValueNode(value, target.Type())
+			if err != nil {
+				return fmt.Errorf("invalid value for %q: %w", key, err)
+			}
+
+			path, err := activeConfigFile(fangsCfg)
+			if err != nil {
+				if errors.Is(err, errNoConfigFile) {
+					return fmt.Errorf("%w (create one with: %s config init)", err, id.Name)
+				}
+				return err
+			}
+
+			if !isYAMLFile(path) {
+				return fmt.Errorf("only yaml configuration files can be updated, got: %s", path)
+			}
+
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("unable to read configuration file: %w", err)
+			}
+
+			contents, err = setYAMLValue(contents, strings.Split(key, "."), node)
+			if err != nil {
+				return fmt.Errorf("unable to update configuration file %s: %w", path, err)
+			}
+
+			return replaceConfigFile(cmd, internalApp, path, contents)
+		},
+	}
+}
+
+// yamlValueNode parses the value as yaml, verifying it can be decoded to the given type
+func yamlValueNode(value string, t reflect.Type) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, err
+	}
+
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str"}
+	if len(doc.Content) > 0 {
+		node = doc.Content[0]
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if node.Kind == yaml.ScalarNode && t.Kind() == reflect.String {
+		// values such as 123 or true should remain strings in the configuration file
+		node.Tag = "!!str"
+	}
+
+	var raw any
+	if err := node.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	if _, err := decodeConfigValue(raw, t); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// setYAMLValue sets the value at the given path in the yaml document, creating any missing mappings while retaining
+// existing comments and key ordering
+func setYAMLValue(contents []byte, path []string, value *yaml.Node) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Kind == 0 {
+		// empty document
+		doc.Kind = yaml.DocumentNode
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+
+	node := doc.Content[0]
+	for i, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%q is not a mapping", strings.Join(path[:i], "."))
+		}
+
+		idx := yamlMappingIndex(node, key)
+
+		if i == len(path)-1 {
+			if idx < 0 {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+				break
+			}
+			existing := node.Content[idx+1]
+			value.HeadComment = existing.HeadComment
+			value.LineComment = existing.LineComment
+			value.FootComment = existing.FootComment
+			node.Content[idx+1] = value
+			break
+		}
+
+		if idx < 0 {
+			child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+			node = child
+			continue
+		}
+		node = node.Content[idx+1]
+	}
+
+	return encodeYAML(&doc)
+}
+
+// yamlMappingIndex returns the index of the key node in the mapping node content, or -1 if not found
+func yamlMappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		// keys are matched without case, the same as when loading configuration
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func encodeYAML(node *yaml.Node) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isYAMLFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// replaceConfigFile loads all configurations using the new contents in place of the given configuration file, only
+// replacing the file when the resulting configuration is valid
+func replaceConfigFile(cmd *cobra.Command, internalApp *application, path string, contents []byte) error {
+	tmp, err := createTempConfigFile(path, contents)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp) //nolint:errcheck // the file has been moved on success
+
+	if err := validateConfigFile(cmd, internalApp, path, tmp); err != nil {
+		return err
+	}
+
+	return moveConfigFile(tmp, path)
+}
+
+// createTempConfigFile writes the contents to a new file alongside the given configuration file, retaining the file
+// extension so the configuration format is detected the same way
+func createTempConfigFile(path string, contents []byte) (string, error) {
+	ext := filepath.Ext(path)
+	tmp, err := os.CreateTemp(filepath.Dir(path), strings.TrimSuffix(filepath.Base(path), ext)+"-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("unable to create temporary configuration file: %w", err)
+	}
+
+	_, err = tmp.Write(contents)
+	err = errors.Join(err, tmp.Close())
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("unable to write temporary configuration file: %w", err)
+	}
+	return tmp.Name(), nil
+}
+
+// moveConfigFile replaces the configuration file with the source file, retaining the permissions of any existing file
+func moveConfigFile(src, path string) error {
+	if fi, err := os.Stat(path); err == nil {
+		_ = os.Chmod(src, fi.Mode().Perm())
+	}
+	if err := os.Rename(src, path); err != nil {
+		return fmt.Errorf("unable to update configuration file: %w", err)
+	}
+	return nil
+}
+
+// validateConfigFile loads all configurations using the replacement file in place of the original file
+func validateConfigFile(cmd *cobra.Command, internalApp *application, original, replacement string) error {
+	fangsCfg, err := withConfigFileReplaced(internalApp.setupConfig.FangsConfig, original, replacement)
+	if err != nil {
+		return err
+	}
+	return loadAllConfigs(cmd, internalApp, fangsCfg, allCommandConfigs(internalApp))
+}
@@ -0,0 +1,230 @@
+package clio
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type valueOptions struct {
+	Name     string          `mapstructure:"name"`
+	Password string          `mapstructure:"password"`
+	Enabled  bool            `mapstructure:"enabled"`
+	Count    int             `mapstructure:"count"`
+	Nested   nestedOptions   `mapstructure:"nested"`
+	Labels   map[string]bool `mapstructure:"labels"`
+}
+
+type nestedOptions struct {
+	Names []string `mapstructure:"names"`
+}
+
+func newValueCommandApp(t *testing.T, configFile string) (Application, *cobra.Command) {
+	t.Helper()
+
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	})
+	cfg.FangsConfig.Files = []string{configFile}
+
+	app := New(*cfg)
+	app.(*application).State().RedactStore.Add("password")
+
+	_ = app.SetupCommand(&cobra.Command{}, &valueOptions{
+		Name:     "default-name",
+		Password: "default-password",
+	})
+
+	return app, ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeGetSetSubcommands(true))
+}
+
+func Test_ConfigGetCommand(t *testing.T) {
+	_, configCmd := newValueCommandApp(t, "testdata/.my-app.yaml")
+
+	getCmd, _, err := configCmd.Find([]string{"get"})
+	require.NoError(t, err)
+
+	t.Setenv("MY_APP_COUNT", "3")
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			key:  "name",
+			want: "name\n",
+		},
+		{
+			key:  "password",
+			want: "*******\n",
+		},
+		{
+			key:  "count",
+			want: "3\n",
+		},
+		{
+			key:  "log.level",
+			want: "info\n",
+		},
+		{
+			key:  "nested",
+			want: "names: []\n",
+		},
+		{
+			key:     "bogus",
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			var err error
+			stdout, _ := captureStd(func() {
+				err = getCmd.RunE(getCmd, []string{tt.key})
+			})
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, stdout)
+		})
+	}
+}
+
+func Test_ConfigSetCommand(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), ".my-app.yaml")
+	original := `# the name to use
+name: name # inline
+
+nested:
+  # some names
+  names:
+    - first
+
+# a trailing comment
+`
+	require.NoError(t, os.WriteFile(configFile, []byte(original), 0o600))
+
+	_, configCmd := newValueCommandApp(t, configFile)
+
+	setCmd, _, err := configCmd.Find([]string{"set"})
+	require.NoError(t, err)
+
+	require.NoError(t, setCmd.RunE(setCmd, []string{"name", "123"}))
+	require.NoError(t, setCmd.RunE(setCmd, []string{"nested.names", "[a, b]"}))
+	require.NoError(t, setCmd.RunE(setCmd, []string{"enabled", "true"}))
+	require.NoError(t, setCmd.RunE(setCmd, []string{"labels.some-label", "true"}))
+	require.NoError(t, setCmd.RunE(setCmd, []string{"dev.profile", "cpu"}))
+
+	// note: comments and key order are retained, blank lines are not
+	expected := `# the name to use
+name: "123" # inline
+nested:
+  # some names
+  names: [a, b]
+enabled: true
+labels:
+  some-label: true
+dev:
+  profile: cpu
+
+# a trailing comment
+`
+
+	contents, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(contents))
+
+	// invalid types and values which fail PostLoad should not be written
+	require.ErrorContains(t, setCmd.RunE(setCmd, []string{"count", "many"}), "invalid value")
+	require.ErrorContains(t, setCmd.RunE(setCmd, []string{"dev.profile", "bogus"}), "invalid profile")
+	require.ErrorContains(t, setCmd.RunE(setCmd, []string{"bogus", "value"}), "unknown configuration key")
+
+	contents, err = os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(contents))
+
+	// no leftover temporary files
+	entries, err := os.ReadDir(filepath.Dir(configFile))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func Test_ConfigSetCommand_noConfigFile(t *testing.T) {
+	cfg := NewSetupConfig(Identification{
+		Name: "my-app",
+	})
+	cfg.FangsConfig.Finders = nil
+
+	app := New(*cfg)
+	_ = app.SetupCommand(&cobra.Command{}, &valueOptions{})
+
+	configCmd := ConfigCommand(app, DefaultConfigCommandConfig().WithIncludeGetSetSubcommands(true))
+	setCmd, _, err := configCmd.Find([]string{"set"})
+	require.NoError(t, err)
+
+	require.ErrorIs(t, setCmd.RunE(setCmd, []string{"name", "value"}), errNoConfigFile)
+}
+
+func Test_yamlValueNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		typ     reflect.Type
+		wantTag string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:    "string stays a string",
+			value:   "true",
+			typ:     reflect.TypeFor[string](),
+			wantTag: "!!str",
+		},
+		{
+			name:    "bool",
+			value:   "true",
+			typ:     reflect.TypeFor[*bool](),
+			wantTag: "!!bool",
+		},
+		{
+			name:    "invalid int",
+			value:   "abc",
+			typ:     reflect.TypeFor[int](),
+			wantErr: require.Error,
+		},
+		{
+			name:    "duration",
+			value:   "5s",
+			typ:     reflect.TypeFor[time.Duration](),
+			wantTag: "!!str",
+		},
+		{
+			name:    "list",
+			value:   "[a, b]",
+			typ:     reflect.TypeFor[[]string](),
+			wantTag: "!!seq",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			node, err := yamlValueNode(tt.value, tt.typ)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantTag, node.Tag)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package clio
+
+import (
+	"slices"
+)
+
+// configWarning is a problem found while loading configuration which does not prevent the configuration from being used
+type configWarning struct {
+	// id identifies the problem, each problem is only logged once regardless of how many sources it was found in
+	id      string
+	message string
+}
+
+// recordConfigWarnings records all warnings found while loading configuration
+func (a *application) recordConfigWarnings(warnings []configWarning) {
+	for _, w := range warnings {
+		if !slices.Contains(a.configWarnings, w) {
+			a.configWarnings = append(a.configWarnings, w)
+		}
+	}
+}
+
+// logConfigWarnings logs each recorded warning which has not already been logged
+func (a *application) logConfigWarnings() {
+	if a.state.Logger == nil {
+		return
+	}
+	for _, w := range a.configWarnings {
+		if slices.Contains(a.loggedConfigWarnings, w.id) {
+			continue
+		}
+		a.loggedConfigWarnings = append(a.loggedConfigWarnings, w.id)
+		a.state.Logger.Warn(w.message)
+	}
+}
+
+// configWarningMessages returns the messages for all recorded warnings
+func (a *application) configWarningMessages() []string {
+	var messages []string
+	for _, w := range a.configWarnings {
+		messages = append(messages, w.message)
+	}
+	return messages
+}
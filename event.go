@@ -19,3 +19,31 @@ func ExitEvent(interrupt bool) partybus.Event {
 		Type: ExitEventType,
 	}
 }
+
+// EventSource is the source of events published with State.Publish, identifying the run which published the event so
+// events may be correlated with log entries. Source is the original source of the event.
+type EventSource struct {
+	RunID  string
+	Source any
+}
+
+// EventRunID returns the run ID of an event published with State.Publish
+func EventRunID(event partybus.Event) (string, bool) {
+	source, ok := event.Source.(EventSource)
+	if !ok {
+		return "", false
+	}
+	return source.RunID, true
+}
+
+// Publish publishes the event to the bus, if there is one, with the source of the event wrapped in an EventSource which
+// includes the run ID
+func (s *State) Publish(event partybus.Event) {
+	if s.Bus == nil {
+		return
+	}
+	if s.RunID != "" {
+		event.Source = EventSource{RunID: s.RunID, Source: event.Source}
+	}
+	s.Bus.Publish(event)
+}
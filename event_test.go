@@ -0,0 +1,36 @@
+package clio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wagoodman/go-partybus"
+)
+
+func Test_State_Publish(t *testing.T) {
+	bus := partybus.NewBus()
+	s := State{RunID: "abc", Bus: bus}
+	sub := bus.Subscribe()
+
+	go s.Publish(partybus.Event{Type: "my-event", Source: "the-source", Value: 3})
+
+	event := <-sub.Events()
+	assert.Equal(t, partybus.EventType("my-event"), event.Type)
+	assert.Equal(t, EventSource{RunID: "abc", Source: "the-source"}, event.Source)
+	assert.Equal(t, 3, event.Value)
+
+	runID, ok := EventRunID(event)
+	require.True(t, ok)
+	assert.Equal(t, "abc", runID)
+
+	_, ok = EventRunID(partybus.Event{Type: "my-event", Source: "the-source"})
+	assert.False(t, ok)
+}
+
+func Test_State_Publish_noBus(t *testing.T) {
+	s := State{RunID: "abc"}
+	assert.NotPanics(t, func() {
+		s.Publish(ExitEvent(false))
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	upstreamLogrus "github.com/sirupsen/logrus"
 	"golang.org/x/term"
@@ -39,18 +42,74 @@ func DefaultLogger(clioCfg Config, store redact.Store) (logger.Logger, error) {
 		return discard.New(), nil
 	}
 
-	l, err := logrus.New(
+	fileLevel := cfg.FileLevel
+	if fileLevel == "" {
+		fileLevel = cfg.Level
+	}
+
+	// quiet disables the console entirely, including any component levels
+	consoleLevels := newLogLevels(logger.DisabledLevel, nil)
+	if !cfg.Quiet {
+		consoleLevels = newLogLevels(cfg.Level, cfg.Levels)
+	}
+
+	outputs := []logLevels{consoleLevels}
+	fileLevels := newLogLevels(fileLevel, cfg.Levels)
+	if cfg.FileLocation != "" {
+		outputs = append(outputs, fileLevels)
+	}
+	for _, sink := range cfg.Sinks {
+		outputs = append(outputs, newLogLevels(sink.Level, cfg.Levels))
+	}
+
+	consoleFormat := logFormatter(cfg.Format)
+	if f, ok := consoleFormat.(*logrus.TextFormatter); ok && cfg.stderrIsTerminal() {
+		// the console output may be routed through the UI (see uiLogRouter), so formatting is selected by the terminal
+		// rather than the writer. Note: disabled colors (e.g. NO_COLOR) take precedence.
+		f.ForceFormatting = true
+		f.ForceColors = true
+	}
+
+	consoleFormatter := levelFilterFormatter{
+		// the run ID is only useful for correlating logs collected from several invocations
+		Formatter: omitFieldsFormatter{Formatter: consoleFormat, fields: []string{logRunIDField}},
+		levels:    consoleLevels,
+	}
+
+	// the logger allows the most verbose level of all outputs, with each output filtering by its own levels
+	upstream := upstreamLogrus.New()
+	l, err := logrus.Use(upstream,
 		logrus.Config{
 			EnableConsole: !cfg.Quiet,
-			FileLocation:  cfg.FileLocation,
-			Level:         cfg.Level,
-			Formatter:     adaptLogFormatter(logrus.DefaultTextFormatter()),
+			Level:         mostVerboseLevel(outputs...),
+			Formatter:     consoleFormatter,
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if cfg.FileLocation != "" {
+		logFile, err := openLogFile(cfg.FileLocation, cfg.FileRotation)
+		if err != nil {
+			return nil, fmt.Errorf("unable to setup log file: %w", err)
+		}
+		upstream.AddHook(&logOutputHook{
+			// log files are never colored
+			formatter: logFileFormatter(cfg.Format),
+			levels:    fileLevels,
+			writer:    logFile,
+		})
+	}
+
+	for _, sink := range cfg.Sinks {
+		hook, err := newLogSinkHook(sink, cfg.Levels)
+		if err != nil {
+			return nil, err
+		}
+		upstream.AddHook(hook)
+	}
+
 	if store != nil {
 		l = redact.New(l, store)
 	}
@@ -58,6 +117,35 @@ func DefaultLogger(clioCfg Config, store redact.Store) (logger.Logger, error) {
 	return l, nil
 }
 
+// logFormatter returns the formatter for the log format, all formats include the full timestamp except for text, which
+// is intended for humans
+func logFormatter(format LogFormat) upstreamLogrus.Formatter {
+	switch format {
+	case LogFormatJSON:
+		return &upstreamLogrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+		}
+	case LogFormatLogfmt:
+		return &upstreamLogrus.TextFormatter{
+			DisableColors:    true,
+			FullTimestamp:    true,
+			TimestampFormat:  time.RFC3339Nano,
+			QuoteEmptyFields: true,
+		}
+	}
+	return adaptLogFormatter(logrus.DefaultTextFormatter())
+}
+
+// logFileFormatter returns the formatter for the log format when writing to a file, which never includes colors
+func logFileFormatter(format LogFormat) upstreamLogrus.Formatter {
+	f := logFormatter(format)
+	if textFormatter, ok := f.(*logrus.TextFormatter); ok {
+		textFormatter.DisableColors = true
+		textFormatter.ForceColors = false
+	}
+	return f
+}
+
 func adaptLogFormatter(cfg upstreamLogrus.Formatter) upstreamLogrus.Formatter {
 	var ok bool
 	var textFormatter *logrus.TextFormatter
@@ -95,17 +183,65 @@ func toBool(str string) bool {
 
 var _ LoggerConstructor = DefaultLogger
 
+// LogFormat is the format log entries are written in
+type LogFormat string
+
+const (
+	// LogFormatText writes log entries for humans, with colors when supported
+	LogFormatText LogFormat = "text"
+
+	// LogFormatJSON writes each log entry as a JSON object, including all nested fields
+	LogFormatJSON LogFormat = "json"
+
+	// LogFormatLogfmt writes each log entry as logfmt key=value pairs, including all nested fields
+	LogFormatLogfmt LogFormat = "logfmt"
+)
+
+// LogFormats returns all supported log formats
+func LogFormats() []LogFormat {
+	return []LogFormat{LogFormatText, LogFormatJSON, LogFormatLogfmt}
+}
+
+func parseLogFormat(format string) (LogFormat, error) {
+	f := LogFormat(strings.ToLower(strings.TrimSpace(format)))
+	if f == "" {
+		return LogFormatText, nil
+	}
+	if !slices.Contains(LogFormats(), f) {
+		return "", fmt.Errorf("unknown log format %q (available: %s)", format, LogFormats())
+	}
+	return f, nil
+}
+
+// LogLevels is the logging level for each component, where a component is a logger nested with a "component" field
+type LogLevels map[string]logger.Level
+
+// String formats the levels as a YAML flow mapping, e.g. {eventloop: trace, registry: debug}
+func (l LogLevels) String() string {
+	var entries []string
+	for _, component := range sortedKeys(l) {
+		entries = append(entries, fmt.Sprintf("%s: %s", component, l[component]))
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
 // LoggingConfig contains all logging-related configuration options available to the user via the application config.
 type LoggingConfig struct {
-	Quiet        bool         `yaml:"quiet" json:"quiet" mapstructure:"quiet"` // -q, indicates to not show any status output to stderr
-	Verbosity    int          `yaml:"-" json:"-" mapstructure:"verbosity"`     // -v or -vv , controlling which UI (ETUI vs logging) and what the log level should be
-	Level        logger.Level `yaml:"level" json:"level" mapstructure:"level"` // the log level string hint
-	FileLocation string       `yaml:"file" json:"file" mapstructure:"file"`    // the file path to write logs to
-
+	Quiet          bool              `yaml:"quiet" json:"quiet" mapstructure:"quiet"`                                            // -q, indicates to not show any status output to stderr
+	Verbosity      int               `yaml:"-" json:"-" mapstructure:"verbosity"`                                                // -v or -vv , controlling which UI (ETUI vs logging) and what the log level should be
+	Level          logger.Level      `yaml:"level" json:"level" mapstructure:"level"`                                            // the log level string hint for the console
+	Format         LogFormat         `yaml:"format" json:"format" mapstructure:"format"`                                         // the format to write log entries in (text, json, or logfmt)
+	FileLocation   string            `yaml:"file" json:"file" mapstructure:"file" clio:"path"`                                   // the file path to write logs to
+	FileLevel      logger.Level      `yaml:"file-level" json:"file-level" mapstructure:"file-level"`                             // the log level for the log file, independent of quiet (default: the console level)
+	Levels         LogLevels         `yaml:"levels" json:"levels" mapstructure:"levels"`                                         // the log level for each component, e.g. {eventloop: trace}
+	FlightRecorder int               `yaml:"flight-recorder" json:"flight-recorder" mapstructure:"flight-recorder" clio:"min=0"` // the number of recent log entries kept at every level, written to a file when the application fails (0 disables)
+	Sinks          []LogSink         `yaml:"sinks" json:"sinks" mapstructure:"sinks"`                                            // additional destinations for log entries, each with its own level and format
+	FileRotation   LogRotationConfig `yaml:"file-rotation" json:"file-rotation" mapstructure:"file-rotation"`                    // rotation and retention of the log file
+	Redact         []string          `yaml:"redact" json:"redact" mapstructure:"redact"`                                         // regular expressions and well-known detectors (e.g. bearer-token) of values to redact
+
+	componentLevels  []string         // component levels from the --log-level flag, e.g. "eventloop=trace"
+	redactPatterns   []*regexp.Regexp // the compiled redaction patterns
 	terminalDetector terminalDetector // for testing
-
-	// not implemented upstream
-	// Structured   bool         `yaml:"structured" json:"structured" mapstructure:"structured"`                        // show all log entries as JSON formatted strings
 }
 
 var _ interface {
@@ -120,14 +256,58 @@ func (l *LoggingConfig) PostLoad() error {
 		return fmt.Errorf("unable to select logging level: %w", err)
 	}
 
+	fileLvl, err := l.selectFileLevel()
+	if err != nil {
+		return fmt.Errorf("unable to select file logging level: %w", err)
+	}
+
+	// sinks default to the console level, which is not affected by quiet
+	sinkLvl, err := l.selectLevelIgnoringQuiet()
+	if err != nil {
+		return fmt.Errorf("unable to select log sink level: %w", err)
+	}
+
 	l.Level = lvl
+	l.FileLevel = fileLvl
+
+	// the sinks may be shared with a copy of the config (e.g. the default config), so are never modified in place
+	l.Sinks = slices.Clone(l.Sinks)
+	for i := range l.Sinks {
+		if l.Sinks[i].Level == "" {
+			l.Sinks[i].Level = sinkLvl
+		}
+	}
+
+	levels, err := l.selectComponentLevels()
+	if err != nil {
+		return fmt.Errorf("unable to select component logging levels: %w", err)
+	}
+	l.Levels = levels
+
+	format, err := parseLogFormat(string(l.Format))
+	if err != nil {
+		return err
+	}
+	l.Format = format
+
+	patterns, err := compileRedactPatterns(l.Redact)
+	if err != nil {
+		return err
+	}
+	l.redactPatterns = patterns
 
 	return nil
 }
 
 func (l *LoggingConfig) DescribeFields(d fangs.FieldDescriptionSet) {
 	d.Add(&l.Level, fmt.Sprintf("explicitly set the logging level (available: %s)", logger.Levels()))
-	d.Add(&l.FileLocation, "file path to write logs to")
+	d.Add(&l.Format, fmt.Sprintf("the format to write log entries in (available: %s)", LogFormats()))
+	d.Add(&l.FileLocation, "file path to write logs to, {date} and {pid} are replaced with the current date and process ID")
+	d.Add(&l.Levels, `the logging level for each component, overriding the logging level for loggers nested with a "component" field (e.g. {eventloop: trace})`)
+	d.Add(&l.FlightRecorder, "the number of recent log entries kept at every logging level, regardless of the configured level, which are written to a file in the state directory when the application fails (0 disables)")
+	d.Add(&l.Sinks, fmt.Sprintf("additional destinations for log entries, each with its own level and format (types: %s)", LogSinkTypes()))
+	d.Add(&l.FileLevel, "the logging level for the log file, which is not affected by --quiet (default: the console logging level)")
+	d.Add(&l.Redact, fmt.Sprintf("regular expressions or well-known detectors (available: %s) of values to redact from log entries, the configuration, and error messages, only the capture groups are redacted for expressions which have them", redactDetectorNames()))
 }
 
 func (l *LoggingConfig) selectLevel() (logger.Level, error) {
@@ -137,9 +317,7 @@ func (l *LoggingConfig) selectLevel() (logger.Level, error) {
 	}
 	switch {
 	case l.Quiet:
-		// TODO: this is bad: quiet option trumps all other logging options (such as to a file on disk)
-		// we should be able to quiet the console logging and leave file logging alone...
-		// ... this will be an enhancement for later
+		// quiet only applies to the console, the log file level is selected independently (see selectFileLevel)
 		return logger.DisabledLevel, nil
 
 	case l.Verbosity > 0:
@@ -162,6 +340,71 @@ func (l *LoggingConfig) selectLevel() (logger.Level, error) {
 	return l.Level, nil
 }
 
+// selectFileLevel returns the level for the log file: the explicitly configured file level, otherwise the console
+// level ignoring the quiet option
+func (l *LoggingConfig) selectFileLevel() (logger.Level, error) {
+	if l == nil {
+		return logger.WarnLevel, nil
+	}
+	if l.FileLevel != "" {
+		lvl, err := logger.LevelFromString(string(l.FileLevel))
+		if err != nil {
+			return logger.DisabledLevel, err
+		}
+		return lvl, nil
+	}
+	return l.selectLevelIgnoringQuiet()
+}
+
+// selectLevelIgnoringQuiet returns the console level as if the quiet option was not set
+func (l *LoggingConfig) selectLevelIgnoringQuiet() (logger.Level, error) {
+	if l == nil {
+		return logger.WarnLevel, nil
+	}
+	console := *l
+	console.Quiet = false
+	return console.selectLevel()
+}
+
+// selectComponentLevels returns the level for each component, levels from the --log-level flag take precedence over
+// configured levels
+func (l *LoggingConfig) selectComponentLevels() (LogLevels, error) {
+	if l == nil || (len(l.Levels) == 0 && len(l.componentLevels) == 0) {
+		return nil, nil
+	}
+
+	levels := LogLevels{}
+	for component, level := range l.Levels {
+		lvl, err := logger.LevelFromString(string(level))
+		if err != nil {
+			return nil, fmt.Errorf("component %q: %w", component, err)
+		}
+		levels[strings.ToLower(component)] = lvl
+	}
+
+	for _, value := range l.componentLevels {
+		component, level, ok := strings.Cut(value, "=")
+		component = strings.TrimSpace(component)
+		if !ok || component == "" {
+			return nil, fmt.Errorf("invalid component level %q (expected COMPONENT=LEVEL, e.g. eventloop=trace)", value)
+		}
+		lvl, err := logger.LevelFromString(strings.TrimSpace(level))
+		if err != nil {
+			return nil, fmt.Errorf("component %q: %w", component, err)
+		}
+		levels[strings.ToLower(component)] = lvl
+	}
+	return levels, nil
+}
+
+// stderrIsTerminal returns true if the console (stderr) is a terminal
+func (l *LoggingConfig) stderrIsTerminal() bool {
+	if l.terminalDetector == nil {
+		return stockTerminalDetector{}.StderrIsTerminal()
+	}
+	return l.terminalDetector.StderrIsTerminal()
+}
+
 func (l *LoggingConfig) AllowUI(stdin fs.File) bool {
 	if forceNoTTY(os.Getenv("NO_TTY")) {
 		return false
@@ -222,5 +465,6 @@ func isPipedInput(stdin fs.File) (bool, error) {
 
 func (l *LoggingConfig) AddFlags(flags fangs.FlagSet) {
 	flags.CountVarP(&l.Verbosity, "verbose", "v", "increase verbosity (-v = info, -vv = debug)")
-	flags.BoolVarP(&l.Quiet, "quiet", "q", "suppress all logging output")
+	flags.BoolVarP(&l.Quiet, "quiet", "q", "suppress console logging output, the log file and any other log sinks are unaffected")
+	flags.StringArrayVarP(&l.componentLevels, "log-level", "", "set the logging level for a component, overriding the logging level (e.g. eventloop=trace)")
 }
@@ -0,0 +1,379 @@
+package clio
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anchore/fangs"
+)
+
+const (
+	// logFileDatePlaceholder is replaced with the current date in the log file path, e.g. "app-{date}.log"
+	logFileDatePlaceholder = "{date}"
+
+	// logFilePIDPlaceholder is replaced with the process ID in the log file path, e.g. "app-{pid}.log"
+	logFilePIDPlaceholder = "{pid}"
+
+	logFileDateFormat    = "2006-01-02"
+	rotatedLogTimeFormat = "20060102T150405.000"
+	compressedLogSuffix  = ".gz"
+)
+
+// logFilePathPatterns are the regular expressions matching the values which replace the placeholders and rotation
+// times in log file paths, by the sentinel standing in for each while a path pattern is built
+var logFilePathPatterns = map[string]string{
+	"\x00date\x00": `\d{4}-\d{2}-\d{2}`,
+	"\x00pid\x00":  `\d+`,
+	"\x00time\x00": `\d{8}T\d{6}\.\d{3}`,
+}
+
+// LogRotationConfig contains the options to rotate the log file and retain previous log files
+type LogRotationConfig struct {
+	MaxSize  string        `yaml:"max-size" json:"max-size" mapstructure:"max-size"`                 // rotate the log file once it reaches this size (e.g. "10MB")
+	MaxAge   time.Duration `yaml:"max-age" json:"max-age" mapstructure:"max-age" clio:"min=0s"`      // rotate the log file once it is older than this duration (e.g. "24h")
+	MaxFiles int           `yaml:"max-files" json:"max-files" mapstructure:"max-files" clio:"min=0"` // the number of previous log files to retain (0 retains all)
+	Compress bool          `yaml:"compress" json:"compress" mapstructure:"compress"`                 // gzip rotated log files
+}
+
+var _ interface {
+	fangs.PostLoader
+	fangs.FieldDescriber
+} = (*LogRotationConfig)(nil)
+
+func (r *LogRotationConfig) PostLoad() error {
+	if _, err := parseByteSize(r.MaxSize); err != nil {
+		return fmt.Errorf("invalid log rotation max-size: %w", err)
+	}
+	return nil
+}
+
+func (r *LogRotationConfig) DescribeFields(d fangs.FieldDescriptionSet) {
+	d.Add(&r.MaxSize, `rotate the log file once it reaches this size (e.g. "10MB", default: no size limit)`)
+	d.Add(&r.MaxAge, `rotate the log file once it is older than this duration (e.g. "24h", default: no age limit)`)
+	d.Add(&r.MaxFiles, "the number of previous log files to retain, including logs from previous runs when the file path uses {date} or {pid} (0 retains all)")
+	d.Add(&r.Compress, "gzip rotated log files")
+}
+
+// enabled returns true if the log file is rotated by size or age
+func (r LogRotationConfig) enabled() bool {
+	size, _ := parseByteSize(r.MaxSize)
+	return size > 0 || r.MaxAge > 0
+}
+
+// parseByteSize parses a size such as "512KB" or "10MB", a size without a unit is in bytes
+func parseByteSize(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	if s == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("unable to parse size %q (expected a size such as 512KB, 10MB, or 1GB)", size)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// expandLogFilePath replaces the {date} and {pid} placeholders in the log file path
+func expandLogFilePath(path string, now time.Time) string {
+	return replaceLogFilePlaceholders(path, now.Format(logFileDateFormat), strconv.Itoa(os.Getpid()))
+}
+
+func replaceLogFilePlaceholders(path, date, pid string) string {
+	return strings.NewReplacer(logFileDatePlaceholder, date, logFilePIDPlaceholder, pid).Replace(path)
+}
+
+// logFile writes to the log file, rotating it by size or age and removing previous log files beyond the number of
+// retained files
+type logFile struct {
+	template string // the configured path, possibly with placeholders
+	path     string // the expanded path of the current log file
+	rotation LogRotationConfig
+	maxSize  int64
+	now      func() time.Time
+
+	lock    sync.Mutex
+	file    *os.File
+	size    int64
+	started time.Time
+}
+
+var _ io.WriteCloser = (*logFile)(nil)
+
+func openLogFile(template string, rotation LogRotationConfig) (*logFile, error) {
+	return openLogFileAt(template, rotation, time.Now)
+}
+
+func openLogFileAt(template string, rotation LogRotationConfig, now func() time.Time) (*logFile, error) {
+	maxSize, err := parseByteSize(rotation.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &logFile{
+		template: template,
+		path:     expandLogFilePath(template, now()),
+		rotation: rotation,
+		maxSize:  maxSize,
+		now:      now,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	if err := f.removeExpired(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the current log file. Without rotation the log file is truncated, as each run writes a new log. With
+// rotation, entries are appended until the file is rotated; the age of an existing file is based on its last
+// modification time.
+func (f *logFile) open() error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if f.rotation.enabled() {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	file, err := os.OpenFile(f.path, flags, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.started = f.now()
+	if f.size > 0 {
+		f.started = info.ModTime()
+	}
+	return nil
+}
+
+func (f *logFile) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, fmt.Errorf("unable to rotate log file: %w", err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *logFile) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.file.Close()
+}
+
+// shouldRotate returns true if the log file exceeds the size after the next write or exceeds the age, an empty file is
+// never rotated
+func (f *logFile) shouldRotate(next int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.maxSize > 0 && f.size+next > f.maxSize {
+		return true
+	}
+	return f.rotation.MaxAge > 0 && f.now().Sub(f.started) >= f.rotation.MaxAge
+}
+
+// rotate moves the current log file aside, named with the rotation time (e.g. "app-20240102T150405.000.log"), and
+// opens a new log file
+func (f *logFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	rotated := rotatedLogFilePath(f.path, f.now())
+	if err := os.Rename(f.path, rotated); err != nil {
+		return err
+	}
+
+	if f.rotation.Compress {
+		if err := compressLogFile(rotated); err != nil {
+			return err
+		}
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+	return f.removeExpired()
+}
+
+// removeExpired removes the oldest previous log files beyond the number of retained files
+func (f *logFile) removeExpired() error {
+	if f.rotation.MaxFiles <= 0 {
+		return nil
+	}
+
+	previous, err := previousLogFiles(f.template, f.path)
+	if err != nil {
+		return err
+	}
+	if len(previous) <= f.rotation.MaxFiles {
+		return nil
+	}
+
+	for _, path := range previous[f.rotation.MaxFiles:] {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// previousLogFiles returns all rotated log files and, when the path has placeholders, log files from previous runs,
+// newest first. Only files named exactly as the log file would be are returned, so unrelated files in the same
+// directory are never included.
+func previousLogFiles(template, current string) ([]string, error) {
+	template = filepath.Clean(template)
+	current = filepath.Clean(current)
+	pattern := replaceLogFilePlaceholders(template, "*", "*")
+	previous := previousLogFilesRegex(template)
+
+	var matches []string
+	for _, p := range []string{pattern, rotatedLogFilePattern(pattern)} {
+		m, err := filepath.Glob(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range m {
+			if previous.MatchString(path) {
+				matches = append(matches, path)
+			}
+		}
+	}
+
+	type previousFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []previousFile
+	for _, path := range matches {
+		if path == current || slices.ContainsFunc(files, func(f previousFile) bool { return f.path == path }) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, previousFile{path: path, modTime: info.ModTime()})
+	}
+
+	slices.SortFunc(files, func(a, b previousFile) int {
+		if c := b.modTime.Compare(a.modTime); c != 0 {
+			return c
+		}
+		return strings.Compare(b.path, a.path)
+	})
+
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.path)
+	}
+	return paths, nil
+}
+
+// previousLogFilesRegex returns the regular expression matching exactly the paths the log file is written to and
+// rotated to, compressed or not, for any date, process ID, and rotation time
+func previousLogFilesRegex(template string) *regexp.Regexp {
+	path := replaceLogFilePlaceholders(template, "\x00date\x00", "\x00pid\x00")
+	rotated := rotatedLogFileName(path, "\x00time\x00")
+
+	var replacements []string
+	for sentinel, pattern := range logFilePathPatterns {
+		replacements = append(replacements, sentinel, pattern)
+	}
+	replacer := strings.NewReplacer(replacements...)
+
+	return regexp.MustCompile(fmt.Sprintf("^(?:%s|%s(?:%s)?)$",
+		replacer.Replace(regexp.QuoteMeta(path)),
+		replacer.Replace(regexp.QuoteMeta(rotated)),
+		regexp.QuoteMeta(compressedLogSuffix),
+	))
+}
+
+// rotatedLogFilePath returns the path a log file is rotated to, e.g. "app.log" is rotated to
+// "app-20240102T150405.000.log"
+func rotatedLogFilePath(path string, t time.Time) string {
+	return rotatedLogFileName(path, t.Format(rotatedLogTimeFormat))
+}
+
+func rotatedLogFileName(path, timestamp string) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(path, ext), timestamp, ext)
+}
+
+// rotatedLogFilePattern returns the glob pattern matching all rotated log files, compressed or not
+func rotatedLogFilePattern(path string) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-*%s*", strings.TrimSuffix(path, ext), ext)
+}
+
+// compressLogFile replaces the file with a gzip compressed copy
+func compressLogFile(path string) (err error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+compressedLogSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	w := gzip.NewWriter(out)
+	if _, err := io.Copy(w, in); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	_ = in.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,244 @@
+package clio
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseByteSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr require.ErrorAssertionFunc
+	}{
+		{size: "", want: 0},
+		{size: "100", want: 100},
+		{size: "100B", want: 100},
+		{size: "512kb", want: 512 << 10},
+		{size: "10MB", want: 10 << 20},
+		{size: " 1.5 GB ", want: 3 << 29},
+		{size: "10XB", wantErr: require.Error},
+		{size: "-1MB", wantErr: require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := parseByteSize(tt.size)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_expandLogFilePath(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	got := expandLogFilePath("/logs/app-{date}-{pid}.log", now)
+	assert.Equal(t, "/logs/app-2024-01-02-"+strconv.Itoa(os.Getpid())+".log", got)
+}
+
+// testClock returns the time, which advances by a second each call
+func testClock() func() time.Time {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+func Test_logFile_sizeRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := openLogFileAt(path, LogRotationConfig{MaxSize: "10B", MaxFiles: 2}, testClock())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, "fourth\n", readFile(t, path))
+
+	previous, err := previousLogFiles(path, path)
+	require.NoError(t, err)
+	// the oldest rotated file is removed, retaining only 2 previous log files
+	require.Len(t, previous, 2)
+	assert.Equal(t, "third\n", readFile(t, previous[0]))
+	assert.Equal(t, "second\n", readFile(t, previous[1]))
+}
+
+func Test_logFile_ageRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	f, err := openLogFileAt(path, LogRotationConfig{MaxAge: time.Hour, Compress: true}, func() time.Time { return now })
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	_, err = f.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	// not rotated before the age is reached
+	now = now.Add(time.Minute)
+	_, err = f.Write([]byte("second\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", readFile(t, path))
+
+	now = now.Add(time.Hour)
+	_, err = f.Write([]byte("third\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", readFile(t, path))
+
+	rotated := filepath.Join(dir, "app-20240102T160505.000.log.gz")
+	require.NoFileExists(t, filepath.Join(dir, "app-20240102T160505.000.log"))
+	require.FileExists(t, rotated)
+
+	gz, err := os.Open(rotated)
+	require.NoError(t, err)
+	defer gz.Close()
+	r, err := gzip.NewReader(gz)
+	require.NoError(t, err)
+	contents, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(contents))
+}
+
+func Test_logFile_truncatesWithoutRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("previous run\n"), 0o644))
+
+	f, err := openLogFileAt(path, LogRotationConfig{}, time.Now)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("this run\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, "this run\n", readFile(t, path))
+}
+
+func Test_logFile_appendsWithRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("previous run\n"), 0o644))
+
+	f, err := openLogFileAt(path, LogRotationConfig{MaxSize: "1MB"}, time.Now)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("this run\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, "previous run\nthis run\n", readFile(t, path))
+}
+
+func Test_logFile_retainsPreviousRuns(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "app-{pid}.log")
+
+	// log files from previous runs, oldest first
+	old := time.Now().Add(-time.Hour)
+	for i, pid := range []string{"1", "2", "3"} {
+		path := filepath.Join(dir, "app-"+pid+".log")
+		require.NoError(t, os.WriteFile(path, []byte(pid), 0o644))
+		modTime := old.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	unrelated := filepath.Join(dir, "other.log")
+	require.NoError(t, os.WriteFile(unrelated, nil, 0o644))
+
+	f, err := openLogFileAt(template, LogRotationConfig{MaxFiles: 1}, time.Now)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	assert.FileExists(t, expandLogFilePath(template, time.Now()))
+	assert.FileExists(t, filepath.Join(dir, "app-3.log"))
+	assert.NoFileExists(t, filepath.Join(dir, "app-2.log"))
+	assert.NoFileExists(t, filepath.Join(dir, "app-1.log"))
+	assert.FileExists(t, unrelated)
+}
+
+func Test_previousLogFiles_ignoresUnrelatedFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		previous  []string
+		unrelated []string
+	}{
+		{
+			name:     "rotated",
+			template: "app.log",
+			previous: []string{"app-20240102T150405.000.log", "app-20240102T150406.000.log.gz"},
+			unrelated: []string{
+				"app-backup.log",
+				"app-important.log.txt",
+				"app-20240102T150405.000.log.bak",
+				"app-20240102.log",
+				"other-20240102T150405.000.log",
+			},
+		},
+		{
+			name:     "placeholders",
+			template: "app-{date}-{pid}.log",
+			previous: []string{"app-2024-01-02-123.log", "app-2024-01-02-456-20240102T150405.000.log.gz"},
+			unrelated: []string{
+				"app-2024-01-02.log",
+				"app-notes-123.log",
+				"app-2024-01-02-123-copy.log",
+				"app-2024-01-02-123.log.gz",
+				"app.log",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			template := filepath.Join(dir, tt.template)
+			current := expandLogFilePath(template, time.Now())
+			require.NoError(t, os.WriteFile(current, nil, 0o644))
+
+			var want []string
+			for i, name := range tt.previous {
+				path := filepath.Join(dir, name)
+				require.NoError(t, os.WriteFile(path, nil, 0o644))
+				// newest first
+				modTime := time.Now().Add(-time.Duration(i+1) * time.Minute)
+				require.NoError(t, os.Chtimes(path, modTime, modTime))
+				want = append(want, path)
+			}
+			for _, name := range tt.unrelated {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+			}
+
+			got, err := previousLogFiles(template, current)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+
+			// retention never removes unrelated files
+			f, err := openLogFileAt(template, LogRotationConfig{MaxFiles: 1}, time.Now)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+			for _, name := range tt.unrelated {
+				assert.FileExists(t, filepath.Join(dir, name))
+			}
+			assert.FileExists(t, want[0])
+			assert.NoFileExists(t, want[1])
+		})
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(contents)
+}
@@ -0,0 +1,163 @@
+package clio
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	upstreamLogrus "github.com/sirupsen/logrus"
+
+	"github.com/anchore/go-logger"
+)
+
+// logrusLevel returns the logrus level for the logger level, disabled logging only allows panic entries (which are
+// never logged by clio)
+func logrusLevel(level logger.Level) upstreamLogrus.Level {
+	switch level {
+	case logger.ErrorLevel:
+		return upstreamLogrus.ErrorLevel
+	case logger.WarnLevel:
+		return upstreamLogrus.WarnLevel
+	case logger.InfoLevel:
+		return upstreamLogrus.InfoLevel
+	case logger.DebugLevel:
+		return upstreamLogrus.DebugLevel
+	case logger.TraceLevel:
+		return upstreamLogrus.TraceLevel
+	}
+	return upstreamLogrus.PanicLevel
+}
+
+// logComponentField is the field nested loggers are tagged with to select the level for a component, e.g.
+// log.Nested("component", "eventloop")
+const logComponentField = "component"
+
+// logLevels are the levels for a single log output: the level for each component, otherwise the default level
+type logLevels struct {
+	level      upstreamLogrus.Level
+	components map[string]upstreamLogrus.Level
+}
+
+func newLogLevels(level logger.Level, components LogLevels) logLevels {
+	l := logLevels{level: logrusLevel(level)}
+	for component, level := range components {
+		if l.components == nil {
+			l.components = map[string]upstreamLogrus.Level{}
+		}
+		l.components[strings.ToLower(component)] = logrusLevel(level)
+	}
+	return l
+}
+
+// enabled returns true if the entry is at or above the level for the component of the entry
+func (l logLevels) enabled(entry *upstreamLogrus.Entry) bool {
+	level := l.level
+	if component, ok := entry.Data[logComponentField]; ok {
+		if componentLevel, ok := l.components[strings.ToLower(fmt.Sprint(component))]; ok {
+			level = componentLevel
+		}
+	}
+	return entry.Level <= level
+}
+
+// mostVerbose returns the level which allows the most log entries of any component
+func (l logLevels) mostVerbose() upstreamLogrus.Level {
+	most := l.level
+	for _, level := range l.components {
+		most = max(most, level)
+	}
+	return most
+}
+
+// mostVerboseLevel returns the level which allows the most log entries of all outputs
+func mostVerboseLevel(outputs ...logLevels) logger.Level {
+	most := upstreamLogrus.PanicLevel
+	for _, l := range outputs {
+		most = max(most, l.mostVerbose())
+	}
+	for _, level := range logger.Levels() {
+		if logrusLevel(level) == most {
+			return level
+		}
+	}
+	return logger.DisabledLevel
+}
+
+// levelFilterFormatter only formats entries enabled by the levels, all other entries are formatted as empty so nothing
+// is written. This allows the logger output to have a different level than the logger, which must allow the most
+// verbose level of all outputs and components.
+type levelFilterFormatter struct {
+	upstreamLogrus.Formatter
+	levels logLevels
+}
+
+func (f levelFilterFormatter) Format(entry *upstreamLogrus.Entry) ([]byte, error) {
+	if !f.levels.enabled(entry) {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
+
+// omitFieldsFormatter formats entries without the given fields, such as fields which are only useful when logs are
+// collected from several invocations
+type omitFieldsFormatter struct {
+	upstreamLogrus.Formatter
+	fields []string
+}
+
+func (f omitFieldsFormatter) Format(entry *upstreamLogrus.Entry) ([]byte, error) {
+	omitted := *entry
+	omitted.Data = upstreamLogrus.Fields{}
+	for k, v := range entry.Data {
+		if !slices.Contains(f.fields, k) {
+			omitted.Data[k] = v
+		}
+	}
+	return f.Formatter.Format(&omitted)
+}
+
+// levelWriter is implemented by writers which write entries differently depending on the level, such as syslog
+type levelWriter interface {
+	WriteLevel(level upstreamLogrus.Level, p []byte) error
+}
+
+// logOutputHook writes entries enabled by the levels to an additional output, independent of the logger output
+type logOutputHook struct {
+	formatter upstreamLogrus.Formatter
+	levels    logLevels
+	lock      sync.Mutex
+	writer    io.Writer
+}
+
+var _ upstreamLogrus.Hook = (*logOutputHook)(nil)
+
+func (h *logOutputHook) Levels() []upstreamLogrus.Level {
+	var levels []upstreamLogrus.Level
+	for _, level := range upstreamLogrus.AllLevels {
+		if level <= h.levels.mostVerbose() {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+func (h *logOutputHook) Fire(entry *upstreamLogrus.Entry) error {
+	if !h.levels.enabled(entry) {
+		return nil
+	}
+
+	contents, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if w, ok := h.writer.(levelWriter); ok {
+		return w.WriteLevel(entry.Level, contents)
+	}
+	_, err = h.writer.Write(contents)
+	return err
+}
@@ -0,0 +1,275 @@
+package clio
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+	upstreamLogrus "github.com/sirupsen/logrus"
+
+	"github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/logrus"
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+const (
+	// defaultFlightRecorderEntries is the number of recent log entries kept by the flight recorder by default
+	defaultFlightRecorderEntries = 1000
+
+	flightRecorderFilePrefix = "flight-recorder-"
+	flightRecorderFileSuffix = ".log"
+
+	// maxFlightRecorderFiles is the number of flight recorder files retained in the state directory, the oldest files
+	// are removed when a new file is written
+	maxFlightRecorderFiles = 10
+)
+
+// flightRecorder keeps the most recent log entries at every level in memory, regardless of the configured logging
+// level, so they can be written out when the application fails
+type flightRecorder struct {
+	formatter upstreamLogrus.Formatter
+	lock      sync.Mutex
+	entries   []*upstreamLogrus.Entry
+	next      int
+	full      bool
+}
+
+var _ upstreamLogrus.Hook = (*flightRecorder)(nil)
+
+func newFlightRecorder(size int, formatter upstreamLogrus.Formatter) *flightRecorder {
+	return &flightRecorder{
+		formatter: formatter,
+		entries:   make([]*upstreamLogrus.Entry, size),
+	}
+}
+
+func (r *flightRecorder) Levels() []upstreamLogrus.Level {
+	return upstreamLogrus.AllLevels
+}
+
+func (r *flightRecorder) Fire(entry *upstreamLogrus.Entry) error {
+	// entries are formatted only when written out, the copy does not include the level or message
+	e := entry.Dup()
+	e.Level = entry.Level
+	e.Message = entry.Message
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	return nil
+}
+
+// recent returns all recorded entries, oldest first
+func (r *flightRecorder) recent() []*upstreamLogrus.Entry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.full {
+		return append([]*upstreamLogrus.Entry(nil), r.entries[:r.next]...)
+	}
+	return append(append([]*upstreamLogrus.Entry(nil), r.entries[r.next:]...), r.entries[:r.next]...)
+}
+
+// WriteTo writes all recorded entries, oldest first
+func (r *flightRecorder) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, entry := range r.recent() {
+		contents, err := r.formatter.Format(entry)
+		if err != nil {
+			return total, err
+		}
+		n, err := w.Write(contents)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// dump writes all recorded entries to a new file within the directory, returning the path of the file. Only the most
+// recent files are retained, older files are removed.
+func (r *flightRecorder) dump(dir string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, flightRecorderFilePrefix+now.Format(rotatedLogTimeFormat)+flightRecorderFileSuffix)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := r.WriteTo(f); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return path, removeExpiredFlightRecorderFiles(dir, maxFlightRecorderFiles)
+}
+
+// removeExpiredFlightRecorderFiles removes the oldest flight recorder files beyond the number of retained files, any
+// other files in the directory are never removed
+func removeExpiredFlightRecorderFiles(dir string, retain int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !isFlightRecorderFile(e.Name()) {
+			continue
+		}
+		files = append(files, e.Name())
+	}
+	if len(files) <= retain {
+		return nil
+	}
+
+	// the timestamp format sorts chronologically, so the newest files are last
+	slices.Sort(files)
+	for _, name := range files[:len(files)-retain] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// isFlightRecorderFile returns true if the file name is exactly the name of a flight recorder file, e.g.
+// "flight-recorder-20240102T150405.000.log"
+func isFlightRecorderFile(name string) bool {
+	timestamp, ok := strings.CutPrefix(name, flightRecorderFilePrefix)
+	if !ok {
+		return false
+	}
+	timestamp, ok = strings.CutSuffix(timestamp, flightRecorderFileSuffix)
+	if !ok {
+		return false
+	}
+	_, err := time.Parse(rotatedLogTimeFormat, timestamp)
+	return err == nil
+}
+
+// recordingLogger writes each log entry to the application logger and to the flight recorder, which keeps entries at
+// every level. The logger from any LoggerConstructor is wrapped, so the flight recorder does not depend on how the
+// application logger is built.
+type recordingLogger struct {
+	logger.Logger
+	recorder logger.Logger
+}
+
+var _ interface {
+	logger.Logger
+	logger.Controller
+} = (*recordingLogger)(nil)
+
+func newRecordingLogger(l logger.Logger, r *flightRecorder, store redact.Store) (logger.Logger, error) {
+	upstream := upstreamLogrus.New()
+	recorder, err := logrus.Use(upstream, logrus.Config{
+		EnableConsole: false,
+		Level:         logger.TraceLevel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	upstream.AddHook(r)
+
+	if store != nil {
+		recorder = redact.New(recorder, store)
+	}
+	return &recordingLogger{Logger: l, recorder: recorder}, nil
+}
+
+func (l *recordingLogger) Errorf(format string, args ...any) {
+	l.Logger.Errorf(format, args...)
+	l.recorder.Errorf(format, args...)
+}
+
+func (l *recordingLogger) Error(args ...any) {
+	l.Logger.Error(args...)
+	l.recorder.Error(args...)
+}
+
+func (l *recordingLogger) Warnf(format string, args ...any) {
+	l.Logger.Warnf(format, args...)
+	l.recorder.Warnf(format, args...)
+}
+
+func (l *recordingLogger) Warn(args ...any) {
+	l.Logger.Warn(args...)
+	l.recorder.Warn(args...)
+}
+
+func (l *recordingLogger) Infof(format string, args ...any) {
+	l.Logger.Infof(format, args...)
+	l.recorder.Infof(format, args...)
+}
+
+func (l *recordingLogger) Info(args ...any) {
+	l.Logger.Info(args...)
+	l.recorder.Info(args...)
+}
+
+func (l *recordingLogger) Debugf(format string, args ...any) {
+	l.Logger.Debugf(format, args...)
+	l.recorder.Debugf(format, args...)
+}
+
+func (l *recordingLogger) Debug(args ...any) {
+	l.Logger.Debug(args...)
+	l.recorder.Debug(args...)
+}
+
+func (l *recordingLogger) Tracef(format string, args ...any) {
+	l.Logger.Tracef(format, args...)
+	l.recorder.Tracef(format, args...)
+}
+
+func (l *recordingLogger) Trace(args ...any) {
+	l.Logger.Trace(args...)
+	l.recorder.Trace(args...)
+}
+
+func (l *recordingLogger) WithFields(fields ...any) logger.MessageLogger {
+	return l.Nested(fields...)
+}
+
+func (l *recordingLogger) Nested(fields ...any) logger.Logger {
+	return &recordingLogger{Logger: l.Logger.Nested(fields...), recorder: l.recorder.Nested(fields...)}
+}
+
+// SetOutput sets the output of the application logger, if it can be set
+func (l *recordingLogger) SetOutput(w io.Writer) {
+	if c, ok := l.Logger.(logger.Controller); ok {
+		c.SetOutput(w)
+	}
+}
+
+// GetOutput returns the output of the application logger, if known
+func (l *recordingLogger) GetOutput() io.Writer {
+	if c, ok := l.Logger.(logger.Controller); ok {
+		return c.GetOutput()
+	}
+	return nil
+}
+
+// stateDir returns the directory for application state, such as flight recorder logs, following the XDG base
+// directory specification (e.g. ~/.local/state/<app>)
+func stateDir(appName string) string {
+	return filepath.Join(xdg.StateHome, appName)
+}
@@ -0,0 +1,141 @@
+package clio
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	upstreamLogrus "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+func Test_flightRecorder_keepsMostRecent(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries int
+		want    string
+	}{
+		{
+			name:    "not full",
+			entries: 2,
+			want:    "level=info msg=\"entry 0\"\nlevel=info msg=\"entry 1\"\n",
+		},
+		{
+			name:    "exactly full",
+			entries: 3,
+			want:    "level=info msg=\"entry 0\"\nlevel=info msg=\"entry 1\"\nlevel=info msg=\"entry 2\"\n",
+		},
+		{
+			name:    "wraps around",
+			entries: 5,
+			want:    "level=info msg=\"entry 2\"\nlevel=info msg=\"entry 3\"\nlevel=info msg=\"entry 4\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFlightRecorder(3, &upstreamLogrus.TextFormatter{DisableTimestamp: true, DisableColors: true})
+
+			for i := 0; i < tt.entries; i++ {
+				entry := upstreamLogrus.NewEntry(upstreamLogrus.New())
+				entry.Level = upstreamLogrus.InfoLevel
+				entry.Message = fmt.Sprintf("entry %d", i)
+				require.NoError(t, r.Fire(entry))
+			}
+
+			buf := &bytes.Buffer{}
+			_, err := r.WriteTo(buf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func Test_flightRecorder_dump(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my-app")
+	r := newFlightRecorder(10, &upstreamLogrus.TextFormatter{DisableTimestamp: true, DisableColors: true})
+
+	entry := upstreamLogrus.NewEntry(upstreamLogrus.New())
+	entry.Level = upstreamLogrus.DebugLevel
+	entry.Message = "recorded"
+	require.NoError(t, r.Fire(entry))
+
+	path, err := r.dump(dir, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "flight-recorder-20240102T150405.000.log"), path)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "level=debug msg=recorded\n", string(contents))
+}
+
+func Test_flightRecorder_dumpRemovesOldest(t *testing.T) {
+	dir := t.TempDir()
+	unrelated := []string{"flight-recorder-notes.log", "flight-recorder-20240101T000000.000.log.bak", "other.log"}
+	for _, name := range unrelated {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+
+	r := newFlightRecorder(1, &upstreamLogrus.TextFormatter{DisableTimestamp: true, DisableColors: true})
+	start := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	for i := 0; i < maxFlightRecorderFiles+2; i++ {
+		_, err := r.dump(dir, start.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "flight-recorder-2*.log"))
+	require.NoError(t, err)
+	require.Len(t, matches, maxFlightRecorderFiles)
+	assert.Equal(t, filepath.Join(dir, "flight-recorder-20240102T150407.000.log"), matches[0])
+
+	for _, name := range unrelated {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+}
+
+func Test_State_setupLogger_flightRecorder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	// a custom logger constructor, which knows nothing of the flight recorder
+	constructor := func(cfg Config, store redact.Store) (logger.Logger, error) {
+		return SlogLogger(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: LevelTrace}))(cfg, store)
+	}
+
+	s := State{
+		RunID:       "the-run",
+		RedactStore: redact.NewStore("secret"),
+		Config: Config{
+			Log: &LoggingConfig{Level: logger.WarnLevel, Format: LogFormatLogfmt, FlightRecorder: 10},
+		},
+	}
+	require.NoError(t, s.setupLogger(constructor))
+	require.NotNil(t, s.recorder)
+
+	s.Logger.Trace("trace secret")
+	s.Logger.Nested("component", "eventloop").Warn("warn")
+
+	// the configured level is unaffected
+	assert.NotContains(t, buf.String(), "trace")
+	assert.Contains(t, buf.String(), "warn")
+
+	recorded := &bytes.Buffer{}
+	_, err := s.recorder.WriteTo(recorded)
+	require.NoError(t, err)
+	assert.Contains(t, recorded.String(), `level=trace msg="trace *******" run-id=the-run`)
+	assert.Contains(t, recorded.String(), "level=warning msg=warn component=eventloop run-id=the-run")
+}
+
+func Test_State_setupLogger_flightRecorderDisabled(t *testing.T) {
+	s := State{Config: Config{Log: &LoggingConfig{Level: logger.WarnLevel}}}
+	require.NoError(t, s.setupLogger(DefaultLogger))
+	assert.Nil(t, s.recorder)
+
+	_, wrapped := s.Logger.(*recordingLogger)
+	assert.False(t, wrapped)
+}
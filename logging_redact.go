@@ -0,0 +1,113 @@
+package clio
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+// redactedValue replaces redacted values, the same as the redact store
+const redactedValue = "*******"
+
+// redactDetectors are well-known secrets which may be redacted by name, for patterns with capture groups only the groups
+// are redacted (e.g. the credentials of a URL, but not the host)
+var redactDetectors = map[string][]*regexp.Regexp{
+	"bearer-token": {
+		regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+	},
+	"aws-keys": {
+		regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`),
+		regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`),
+	},
+	"url-userinfo": {
+		regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.\-]*://([^\s/?#@]+)@`),
+	},
+	"private-key": {
+		regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----(.+?)-----END [A-Z ]*PRIVATE KEY-----`),
+	},
+}
+
+func redactDetectorNames() []string {
+	return sortedKeys(redactDetectors)
+}
+
+// compileRedactPatterns returns the patterns for each detector name or regular expression
+func compileRedactPatterns(entries []string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, entry := range entries {
+		if detector, ok := redactDetectors[strings.ToLower(strings.TrimSpace(entry))]; ok {
+			patterns = append(patterns, detector...)
+			continue
+		}
+		pattern, err := regexp.Compile(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q (expected a regular expression or one of %s): %w", entry, redactDetectorNames(), err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// redactPatterns replaces every match of the patterns, or only the capture groups of patterns which have them
+func redactPatterns(s string, patterns []*regexp.Regexp) string {
+	for _, pattern := range patterns {
+		matches := pattern.FindAllStringSubmatchIndex(s, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		var sb strings.Builder
+		last := 0
+		for _, match := range matches {
+			spans := [][2]int{{match[0], match[1]}}
+			if pattern.NumSubexp() > 0 {
+				spans = nil
+				for i := 2; i+1 < len(match); i += 2 {
+					if match[i] >= 0 && match[i] >= last {
+						spans = append(spans, [2]int{match[i], match[i+1]})
+					}
+				}
+			}
+			for _, span := range spans {
+				sb.WriteString(s[last:span[0]])
+				sb.WriteString(redactedValue)
+				last = span[1]
+			}
+		}
+		sb.WriteString(s[last:])
+		s = sb.String()
+	}
+	return s
+}
+
+// redactStore redacts exact values, the same as redact.Store, and values matching the configured redaction patterns
+type redactStore struct {
+	redact.Store
+	lock     sync.RWMutex
+	patterns []*regexp.Regexp
+}
+
+var _ redact.Store = (*redactStore)(nil)
+
+func newRedactStore(values ...string) *redactStore {
+	return &redactStore{Store: redact.NewStore(values...)}
+}
+
+func (s *redactStore) RedactString(str string) string {
+	str = s.Store.RedactString(str)
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return redactPatterns(str, s.patterns)
+}
+
+// setPatterns replaces the redaction patterns
+func (s *redactStore) setPatterns(patterns []*regexp.Regexp) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.patterns = slices.Clone(patterns)
+}
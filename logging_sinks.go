@@ -0,0 +1,115 @@
+package clio
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/anchore/fangs"
+	"github.com/anchore/go-logger"
+)
+
+// LogSinkType is the kind of destination a log sink writes to
+type LogSinkType string
+
+const (
+	// LogSinkStderr writes log entries to stderr
+	LogSinkStderr LogSinkType = "stderr"
+
+	// LogSinkStdout writes log entries to stdout
+	LogSinkStdout LogSinkType = "stdout"
+
+	// LogSinkFile writes log entries to a file, see LogSink.Path and LogSink.Rotation
+	LogSinkFile LogSinkType = "file"
+
+	// LogSinkSyslog writes log entries using the syslog protocol, see LogSink.Address and LogSink.Tag
+	LogSinkSyslog LogSinkType = "syslog"
+)
+
+// LogSinkTypes returns all supported log sink types
+func LogSinkTypes() []LogSinkType {
+	return []LogSinkType{LogSinkStderr, LogSinkStdout, LogSinkFile, LogSinkSyslog}
+}
+
+// LogSink is an additional destination for log entries, with its own level and format
+type LogSink struct {
+	Type     LogSinkType       `yaml:"type" json:"type" mapstructure:"type"`             // the destination to write to (stderr, stdout, file, or syslog)
+	Level    logger.Level      `yaml:"level" json:"level" mapstructure:"level"`          // the log level for the sink, not affected by --quiet (default: the console level)
+	Format   LogFormat         `yaml:"format" json:"format" mapstructure:"format"`       // the format to write log entries in (text, json, or logfmt)
+	Path     string            `yaml:"path" json:"path" mapstructure:"path" clio:"path"` // the file path to write logs to (file sinks only)
+	Rotation LogRotationConfig `yaml:"rotation" json:"rotation" mapstructure:"rotation"` // rotation and retention of the log file (file sinks only)
+	Address  string            `yaml:"address" json:"address" mapstructure:"address"`    // the syslog address, e.g. "unixgram:///dev/log" or "udp://localhost:514" (syslog sinks only, default: the local syslog)
+	Tag      string            `yaml:"tag" json:"tag" mapstructure:"tag"`                // the syslog tag (syslog sinks only, default: the program name)
+}
+
+var _ fangs.PostLoader = (*LogSink)(nil)
+
+func (s *LogSink) PostLoad() error {
+	s.Type = LogSinkType(strings.ToLower(strings.TrimSpace(string(s.Type))))
+	if !slices.Contains(LogSinkTypes(), s.Type) {
+		return fmt.Errorf("unknown log sink type %q (available: %s)", s.Type, LogSinkTypes())
+	}
+
+	if s.Type == LogSinkFile && s.Path == "" {
+		return fmt.Errorf("log sink of type %q requires a path", s.Type)
+	}
+
+	if s.Level != "" {
+		lvl, err := logger.LevelFromString(string(s.Level))
+		if err != nil {
+			return fmt.Errorf("invalid %s log sink level: %w", s.Type, err)
+		}
+		s.Level = lvl
+	}
+
+	format, err := parseLogFormat(string(s.Format))
+	if err != nil {
+		return fmt.Errorf("invalid %s log sink format: %w", s.Type, err)
+	}
+	s.Format = format
+
+	return nil
+}
+
+// newLogSinkHook returns the hook writing log entries to the sink, using the component levels for the sink level
+func newLogSinkHook(sink LogSink, components LogLevels) (*logOutputHook, error) {
+	writer, err := openLogSink(sink)
+	if err != nil {
+		return nil, fmt.Errorf("unable to setup %s log sink: %w", sink.Type, err)
+	}
+
+	formatter := logFileFormatter(sink.Format)
+	if sink.Type == LogSinkStderr || sink.Type == LogSinkStdout {
+		formatter = logFormatter(sink.Format)
+	}
+
+	return &logOutputHook{
+		formatter: formatter,
+		levels:    newLogLevels(sink.Level, components),
+		writer:    writer,
+	}, nil
+}
+
+func openLogSink(sink LogSink) (io.Writer, error) {
+	switch sink.Type {
+	case LogSinkStderr:
+		return os.Stderr, nil
+	case LogSinkStdout:
+		return os.Stdout, nil
+	case LogSinkFile:
+		f, err := openLogFile(sink.Path, sink.Rotation)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	case LogSinkSyslog:
+		w, err := openSyslog(sink.Address, sink.Tag)
+		if err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+	return nil, fmt.Errorf("unknown log sink type %q", sink.Type)
+}
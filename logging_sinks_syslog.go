@@ -0,0 +1,68 @@
+//go:build !windows && !plan9
+
+package clio
+
+import (
+	"fmt"
+	"log/syslog"
+	"net/url"
+	"strings"
+
+	upstreamLogrus "github.com/sirupsen/logrus"
+)
+
+// syslogWriter writes each log entry with the syslog severity matching the level of the entry
+type syslogWriter struct {
+	*syslog.Writer
+}
+
+var _ levelWriter = (*syslogWriter)(nil)
+
+// openSyslog connects to the syslog address, e.g. "unixgram:///dev/log" or "udp://localhost:514", or to the local syslog
+// when no address is given
+func openSyslog(address, tag string) (*syslogWriter, error) {
+	network, raddr, err := parseSyslogAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogWriter{Writer: w}, nil
+}
+
+func parseSyslogAddress(address string) (network, raddr string, err error) {
+	if address == "" {
+		return "", "", nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil || u.Scheme == "" {
+		return "", "", fmt.Errorf("invalid syslog address %q (expected e.g. unixgram:///dev/log or udp://localhost:514)", address)
+	}
+
+	switch u.Scheme {
+	case "unix", "unixgram":
+		return u.Scheme, u.Path, nil
+	case "udp", "tcp":
+		return u.Scheme, u.Host, nil
+	}
+	return "", "", fmt.Errorf("unsupported syslog network %q (available: unix, unixgram, udp, tcp)", u.Scheme)
+}
+
+func (w *syslogWriter) WriteLevel(level upstreamLogrus.Level, p []byte) error {
+	msg := strings.TrimSuffix(string(p), "\n")
+	switch level {
+	case upstreamLogrus.PanicLevel, upstreamLogrus.FatalLevel:
+		return w.Crit(msg)
+	case upstreamLogrus.ErrorLevel:
+		return w.Err(msg)
+	case upstreamLogrus.WarnLevel:
+		return w.Warning(msg)
+	case upstreamLogrus.InfoLevel:
+		return w.Info(msg)
+	}
+	return w.Debug(msg)
+}
@@ -0,0 +1,74 @@
+//go:build !windows && !plan9
+
+package clio
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+)
+
+func Test_parseSyslogAddress(t *testing.T) {
+	tests := []struct {
+		address     string
+		wantNetwork string
+		wantAddress string
+		wantErr     require.ErrorAssertionFunc
+	}{
+		{address: ""},
+		{address: "unixgram:///dev/log", wantNetwork: "unixgram", wantAddress: "/dev/log"},
+		{address: "unix:///var/run/syslog", wantNetwork: "unix", wantAddress: "/var/run/syslog"},
+		{address: "udp://localhost:514", wantNetwork: "udp", wantAddress: "localhost:514"},
+		{address: "tcp://10.0.0.1:6514", wantNetwork: "tcp", wantAddress: "10.0.0.1:6514"},
+		{address: "/dev/log", wantErr: require.Error},
+		{address: "http://localhost:514", wantErr: require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			network, address, err := parseSyslogAddress(tt.address)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.wantNetwork, network)
+			assert.Equal(t, tt.wantAddress, address)
+		})
+	}
+}
+
+func Test_newLogger_syslogSink(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	cfg := &LoggingConfig{
+		Level: logger.ErrorLevel,
+		Sinks: []LogSink{
+			{Type: LogSinkSyslog, Address: "unixgram://" + socket, Tag: "my-app", Level: logger.InfoLevel, Format: LogFormatLogfmt},
+		},
+	}
+	for i := range cfg.Sinks {
+		require.NoError(t, cfg.Sinks[i].PostLoad())
+	}
+
+	log, err := DefaultLogger(Config{Log: cfg}, nil)
+	require.NoError(t, err)
+	log.Debug("not sent")
+	log.Warn("sent")
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+
+	// <12> is the user facility (1) with the warning severity (4)
+	msg := string(buf[:n])
+	assert.Regexp(t, `^<12>.* my-app\[[0-9]+\]: time=.* level=warning msg=sent\n$`, msg)
+}
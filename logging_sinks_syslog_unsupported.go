@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package clio
+
+import (
+	"errors"
+	"io"
+)
+
+func openSyslog(_, _ string) (io.Writer, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,149 @@
+package clio
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+)
+
+func TestLogSink_PostLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		sink    LogSink
+		want    LogSink
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "normalizes the sink",
+			sink: LogSink{Type: "StdErr", Level: "DEBUG"},
+			want: LogSink{Type: LogSinkStderr, Level: logger.DebugLevel, Format: LogFormatText},
+		},
+		{
+			name: "file sink",
+			sink: LogSink{Type: "file", Path: "app.json", Format: "json"},
+			want: LogSink{Type: LogSinkFile, Path: "app.json", Format: LogFormatJSON},
+		},
+		{
+			name:    "file sink requires a path",
+			sink:    LogSink{Type: "file"},
+			wantErr: require.Error,
+		},
+		{
+			name:    "unknown type",
+			sink:    LogSink{Type: "carrier-pigeon"},
+			wantErr: require.Error,
+		},
+		{
+			name:    "bogus level",
+			sink:    LogSink{Type: "stderr", Level: "bogosity"},
+			wantErr: require.Error,
+		},
+		{
+			name:    "bogus format",
+			sink:    LogSink{Type: "stderr", Format: "xml"},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			err := tt.sink.PostLoad()
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, tt.sink)
+		})
+	}
+}
+
+func TestLoggingConfig_PostLoad_sinkLevels(t *testing.T) {
+	cfg := &LoggingConfig{
+		Quiet:     true,
+		Verbosity: 2,
+		Sinks: []LogSink{
+			{Type: LogSinkStderr},
+			{Type: LogSinkStdout, Level: logger.ErrorLevel},
+		},
+	}
+	require.NoError(t, cfg.PostLoad())
+
+	// sinks default to the console level, ignoring quiet
+	assert.Equal(t, logger.DisabledLevel, cfg.Level)
+	assert.Equal(t, logger.DebugLevel, cfg.Sinks[0].Level)
+	assert.Equal(t, logger.ErrorLevel, cfg.Sinks[1].Level)
+}
+
+func TestLoggingConfig_PostLoad_sharedSinks(t *testing.T) {
+	defaults := LoggingConfig{Sinks: []LogSink{{Type: LogSinkStderr}}}
+
+	cfg := defaults
+	cfg.Verbosity = 2
+	require.NoError(t, cfg.PostLoad())
+	assert.Equal(t, logger.DebugLevel, cfg.Sinks[0].Level)
+
+	// sink levels are selected on the copy only
+	assert.Equal(t, logger.Level(""), defaults.Sinks[0].Level)
+}
+
+func Test_newLogger_sinks(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "app.json")
+	textFile := filepath.Join(dir, "app.log")
+
+	cfg := &LoggingConfig{
+		Level: logger.WarnLevel,
+		Sinks: []LogSink{
+			{Type: LogSinkFile, Path: jsonFile, Level: logger.DebugLevel, Format: LogFormatJSON},
+			{Type: LogSinkFile, Path: textFile, Level: logger.ErrorLevel},
+		},
+	}
+	for i := range cfg.Sinks {
+		require.NoError(t, cfg.Sinks[i].PostLoad())
+	}
+
+	log, err := DefaultLogger(Config{Log: cfg}, nil)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	log.(logger.Controller).SetOutput(buf)
+	log.Debug("debug entry")
+	log.Error("error entry")
+
+	// the console only has the error entry
+	assert.Equal(t, "[0000] ERROR error entry\n", stripAnsi(buf.String()))
+
+	contents, err := os.ReadFile(jsonFile)
+	require.NoError(t, err)
+	var messages []string
+	for _, line := range bytes.Split(bytes.TrimSpace(contents), []byte("\n")) {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(line, &entry))
+		messages = append(messages, entry["msg"].(string))
+	}
+	assert.Equal(t, []string{"debug entry", "error entry"}, messages)
+
+	contents, err = os.ReadFile(textFile)
+	require.NoError(t, err)
+	assert.Equal(t, "[0000] ERROR error entry\n", string(contents))
+}
+
+func Test_newLogger_sinkError(t *testing.T) {
+	cfg := &LoggingConfig{
+		Level: logger.WarnLevel,
+		Sinks: []LogSink{
+			{Type: LogSinkFile, Path: filepath.Join(t.TempDir(), "missing", "app.log")},
+		},
+	}
+	_, err := DefaultLogger(Config{Log: cfg}, nil)
+	require.ErrorContains(t, err, "unable to setup file log sink")
+}
@@ -0,0 +1,250 @@
+package clio
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/discard"
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+// LevelTrace is the slog level used for trace log entries, below slog.LevelDebug
+const LevelTrace = slog.LevelDebug - 4
+
+// NewSlogHandler returns a slog.Handler which writes records to the logger, so records from log/slog (and the log
+// package, when installed as the slog default) are subject to the same redaction, levels, and outputs as the logger.
+// Use State.Logger, which includes redaction, as the logger.
+func NewSlogHandler(log logger.Logger) slog.Handler {
+	if log == nil {
+		log = discard.New()
+	}
+	return &slogHandler{log: log}
+}
+
+type slogHandler struct {
+	log   logger.Logger
+	group string // the prefix for attribute keys of the current group, e.g. "request."
+}
+
+var _ slog.Handler = (*slogHandler)(nil)
+
+// Enabled always returns true, the logger decides which records are written
+func (h *slogHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := logger.Fields{}
+	r.Attrs(func(a slog.Attr) bool {
+		addSlogAttr(fields, h.group, a)
+		return true
+	})
+
+	var log logger.MessageLogger = h.log
+	if len(fields) > 0 {
+		log = h.log.WithFields(fields)
+	}
+
+	switch {
+	case r.Level >= slog.LevelError:
+		log.Error(r.Message)
+	case r.Level >= slog.LevelWarn:
+		log.Warn(r.Message)
+	case r.Level >= slog.LevelInfo:
+		log.Info(r.Message)
+	case r.Level >= slog.LevelDebug:
+		log.Debug(r.Message)
+	default:
+		log.Trace(r.Message)
+	}
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := logger.Fields{}
+	for _, a := range attrs {
+		addSlogAttr(fields, h.group, a)
+	}
+	return &slogHandler{log: h.log.Nested(fields), group: h.group}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{log: h.log, group: h.group + name + "."}
+}
+
+// addSlogAttr adds the attribute as a field, attributes within groups are added with the group as a key prefix
+func addSlogAttr(fields logger.Fields, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			addSlogAttr(fields, prefix, ga)
+		}
+		return
+	}
+
+	fields[prefix+a.Key] = a.Value.Any()
+}
+
+// SlogLogger returns a LoggerConstructor which writes to the slog handler, using the configured logging levels
+// (including component levels) and redaction. Outputs configured in the logging config, such as the log file and sinks,
+// are the responsibility of the handler.
+func SlogLogger(handler slog.Handler) LoggerConstructor {
+	return func(clioCfg Config, store redact.Store) (logger.Logger, error) {
+		cfg := clioCfg.Log
+		if cfg == nil || handler == nil {
+			return discard.New(), nil
+		}
+
+		components := map[string]slog.Level{}
+		for component, level := range cfg.Levels {
+			components[strings.ToLower(component)] = slogLevel(level)
+		}
+
+		var l logger.Logger = &slogLogger{
+			handler:    handler,
+			level:      slogLevel(cfg.Level),
+			components: components,
+		}
+
+		if store != nil {
+			l = redact.New(l, store)
+		}
+		return l, nil
+	}
+}
+
+// slogLevel returns the slog level for the logger level, disabled logging is above every slog level
+func slogLevel(level logger.Level) slog.Level {
+	switch level {
+	case logger.ErrorLevel:
+		return slog.LevelError
+	case logger.WarnLevel:
+		return slog.LevelWarn
+	case logger.InfoLevel:
+		return slog.LevelInfo
+	case logger.DebugLevel:
+		return slog.LevelDebug
+	case logger.TraceLevel:
+		return LevelTrace
+	}
+	return slog.LevelError + 1
+}
+
+// slogLogger is a logger writing records to a slog handler
+type slogLogger struct {
+	handler    slog.Handler
+	level      slog.Level
+	components map[string]slog.Level
+}
+
+var _ logger.Logger = (*slogLogger)(nil)
+
+func (l *slogLogger) log(level slog.Level, msg string) {
+	if level < l.level || !l.handler.Enabled(context.Background(), level) {
+		return
+	}
+	// note: the caller is not recorded, as it would always be within this logger
+	_ = l.handler.Handle(context.Background(), slog.NewRecord(time.Now(), level, msg, 0))
+}
+
+func (l *slogLogger) Errorf(format string, args ...any) {
+	l.log(slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Error(args ...any) {
+	l.log(slog.LevelError, fmt.Sprint(args...))
+}
+
+func (l *slogLogger) Warnf(format string, args ...any) {
+	l.log(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Warn(args ...any) {
+	l.log(slog.LevelWarn, fmt.Sprint(args...))
+}
+
+func (l *slogLogger) Infof(format string, args ...any) {
+	l.log(slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Info(args ...any) {
+	l.log(slog.LevelInfo, fmt.Sprint(args...))
+}
+
+func (l *slogLogger) Debugf(format string, args ...any) {
+	l.log(slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Debug(args ...any) {
+	l.log(slog.LevelDebug, fmt.Sprint(args...))
+}
+
+func (l *slogLogger) Tracef(format string, args ...any) {
+	l.log(LevelTrace, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Trace(args ...any) {
+	l.log(LevelTrace, fmt.Sprint(args...))
+}
+
+func (l *slogLogger) WithFields(fields ...any) logger.MessageLogger {
+	return l.Nested(fields...)
+}
+
+// Nested returns a logger with the fields attached to every record, a "component" field selects the component level
+func (l *slogLogger) Nested(fields ...any) logger.Logger {
+	attrs := slogAttrs(fields...)
+	nested := &slogLogger{
+		handler:    l.handler.WithAttrs(attrs),
+		level:      l.level,
+		components: l.components,
+	}
+	for _, a := range attrs {
+		if a.Key != logComponentField {
+			continue
+		}
+		if level, ok := l.components[strings.ToLower(a.Value.String())]; ok {
+			nested.level = level
+		}
+	}
+	return nested
+}
+
+// slogAttrs converts logger fields, which are key-value pairs and logger.Fields maps, to slog attributes
+func slogAttrs(fields ...any) []slog.Attr {
+	var attrs []slog.Attr
+	var key any
+	hasKey := false
+	for _, f := range fields {
+		if m, ok := f.(logger.Fields); ok {
+			for _, k := range sortedKeys(m) {
+				attrs = append(attrs, slog.Any(k, m[k]))
+			}
+			continue
+		}
+		if !hasKey {
+			key, hasKey = f, true
+			continue
+		}
+		attrs = append(attrs, slog.Any(fmt.Sprintf("%s", key), f))
+		hasKey = false
+	}
+	return attrs
+}
@@ -0,0 +1,99 @@
+package clio
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+func Test_slogHandler(t *testing.T) {
+	log, err := DefaultLogger(Config{Log: &LoggingConfig{Level: logger.InfoLevel, Format: LogFormatLogfmt}}, redact.NewStore("secret"))
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	log.(logger.Controller).SetOutput(buf)
+
+	l := slog.New(NewSlogHandler(log)).With("component", "dependency")
+	l.Debug("not logged")
+	l.WithGroup("request").Info("handled secret", "id", 7, slog.Group("user", "name", "secret"))
+	l.Error("failed")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `level=info msg="handled \*\*\*\*\*\*\*" component=dependency request.id=7 request.user.name="\*\*\*\*\*\*\*"$`, string(lines[0]))
+	assert.Regexp(t, `level=error msg=failed component=dependency$`, string(lines[1]))
+}
+
+func Test_SlogLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: LevelTrace,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+
+	cfg := &LoggingConfig{Level: logger.WarnLevel, Levels: LogLevels{"eventloop": logger.TraceLevel}}
+	log, err := SlogLogger(handler)(Config{Log: cfg}, redact.NewStore("secret"))
+	require.NoError(t, err)
+
+	log.Info("not logged")
+	log.Warnf("warn %s", "secret")
+	log.WithFields("key", "value").Error("error")
+	log.Nested("component", "eventloop").Trace("trace")
+	log.Nested(logger.Fields{"component": "other"}).Debug("not logged")
+
+	assert.Equal(t, `level=WARN msg="warn *******"
+level=ERROR msg=error key=value
+level=DEBUG-4 msg=trace component=eventloop
+`, buf.String())
+}
+
+func Test_SlogLogger_noConfig(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log, err := SlogLogger(slog.NewTextHandler(buf, nil))(Config{}, nil)
+	require.NoError(t, err)
+	log.Error("not logged")
+	assert.Empty(t, buf.String())
+}
+
+func Test_Application_Setup_SlogDefault(t *testing.T) {
+	defaultLogger, logOutput, logFlags := slog.Default(), log.Writer(), log.Flags()
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+		log.SetOutput(logOutput)
+		log.SetFlags(logFlags)
+	})
+
+	cfg := NewSetupConfig(Identification{Name: "puppy", Version: "2.0"}).
+		WithLoggingConfig(LoggingConfig{Level: logger.InfoLevel}).
+		WithSlogDefault()
+
+	app := New(*cfg)
+	cmd := app.SetupRootCommand(&cobra.Command{
+		Run: func(cmd *cobra.Command, args []string) {},
+	})
+	cmd.SetArgs(nil)
+	require.NoError(t, cmd.Execute())
+
+	state := app.(*application).State()
+	state.RedactStore.Add("secret")
+	buf := &bytes.Buffer{}
+	state.Logger.(logger.Controller).SetOutput(buf)
+
+	slog.Info("from slog", "token", "secret")
+	slog.Debug("not logged")
+	log.Print("from log")
+
+	assert.Equal(t, "[0000]  INFO from slog token=*******\n[0000]  INFO from log\n", stripAnsi(buf.String()))
+}
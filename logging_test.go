@@ -2,10 +2,12 @@ package clio
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -120,8 +122,9 @@ func TestLoggingConfig_AddFlags(t *testing.T) {
 		{
 			name: "flags are registered",
 			flags: map[string]string{
-				"quiet":   "q",
-				"verbose": "v",
+				"quiet":     "q",
+				"verbose":   "v",
+				"log-level": "",
 			},
 		},
 	}
@@ -480,3 +483,226 @@ func Test_adaptLogFormatter(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseLogFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    LogFormat
+		wantErr require.ErrorAssertionFunc
+	}{
+		{format: "", want: LogFormatText},
+		{format: "text", want: LogFormatText},
+		{format: "JSON", want: LogFormatJSON},
+		{format: " logfmt ", want: LogFormatLogfmt},
+		{format: "xml", wantErr: require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := parseLogFormat(tt.format)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_newLogger_formats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format LogFormat
+		assert func(t *testing.T, out string)
+	}{
+		{
+			name:   "json",
+			format: LogFormatJSON,
+			assert: func(t *testing.T, out string) {
+				var entry map[string]any
+				require.NoError(t, json.Unmarshal([]byte(out), &entry))
+				assert.Equal(t, "info", entry["level"])
+				assert.Equal(t, "test *******", entry["msg"])
+				assert.Equal(t, "eventloop", entry["component"])
+				_, err := time.Parse(time.RFC3339Nano, entry["time"].(string))
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "logfmt",
+			format: LogFormatLogfmt,
+			assert: func(t *testing.T, out string) {
+				assert.Regexp(t, `^time="?[0-9T:.+Z-]+"? level=info msg="test \*\*\*\*\*\*\*" component=eventloop\n$`, out)
+			},
+		},
+		{
+			name:   "text",
+			format: LogFormatText,
+			assert: func(t *testing.T, out string) {
+				assert.Equal(t, "[0000]  INFO test ******* component=eventloop\n", stripAnsi(out))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, err := DefaultLogger(Config{Log: &LoggingConfig{Level: logger.InfoLevel, Format: tt.format}}, redact.NewStore("secret"))
+			require.NoError(t, err)
+
+			buf := &bytes.Buffer{}
+			log.(logger.Controller).SetOutput(buf)
+			log.Nested("component", "eventloop").Info("test secret")
+
+			tt.assert(t, buf.String())
+		})
+	}
+}
+
+func TestLoggingConfig_selectFileLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *LoggingConfig
+		want    logger.Level
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "no config",
+			cfg:  nil,
+			want: logger.WarnLevel,
+		},
+		{
+			name: "defaults to the console level",
+			cfg:  &LoggingConfig{Level: logger.ErrorLevel},
+			want: logger.ErrorLevel,
+		},
+		{
+			name: "quiet does not disable the file level",
+			cfg:  &LoggingConfig{Quiet: true, Verbosity: 2},
+			want: logger.DebugLevel,
+		},
+		{
+			name: "set file level directly",
+			cfg:  &LoggingConfig{Quiet: true, Level: logger.WarnLevel, FileLevel: logger.TraceLevel},
+			want: logger.TraceLevel,
+		},
+		{
+			name:    "bogus file level set",
+			cfg:     &LoggingConfig{FileLevel: logger.Level("bogosity")},
+			want:    logger.DisabledLevel,
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := tt.cfg.selectFileLevel()
+			assert.Equal(t, tt.want, got)
+			tt.wantErr(t, err)
+		})
+	}
+}
+
+func Test_newLogger_fileLevel(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	cfg := &LoggingConfig{Quiet: true, FileLocation: logFile, FileLevel: logger.DebugLevel}
+	require.NoError(t, cfg.PostLoad())
+
+	log, err := DefaultLogger(Config{Log: cfg}, nil)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	log.(logger.Controller).SetOutput(buf)
+	log.Debug("to the file")
+	log.Trace("nowhere")
+
+	// quiet suppresses the console, but not the log file
+	assert.Empty(t, buf.String())
+
+	contents, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, "[0000] DEBUG to the file\n", string(contents))
+}
+
+func TestLoggingConfig_selectComponentLevels(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *LoggingConfig
+		want    LogLevels
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "no config",
+			cfg:  nil,
+		},
+		{
+			name: "no component levels",
+			cfg:  &LoggingConfig{},
+		},
+		{
+			name: "configured levels",
+			cfg:  &LoggingConfig{Levels: LogLevels{"EventLoop": "trace", "registry": "debug"}},
+			want: LogLevels{"eventloop": logger.TraceLevel, "registry": logger.DebugLevel},
+		},
+		{
+			name: "flags take precedence",
+			cfg: &LoggingConfig{
+				Levels:          LogLevels{"eventloop": "trace", "registry": "debug"},
+				componentLevels: []string{"registry=warn", "cache = error"},
+			},
+			want: LogLevels{"eventloop": logger.TraceLevel, "registry": logger.WarnLevel, "cache": logger.ErrorLevel},
+		},
+		{
+			name:    "bogus configured level",
+			cfg:     &LoggingConfig{Levels: LogLevels{"eventloop": "bogosity"}},
+			wantErr: require.Error,
+		},
+		{
+			name:    "bogus flag level",
+			cfg:     &LoggingConfig{componentLevels: []string{"eventloop=bogosity"}},
+			wantErr: require.Error,
+		},
+		{
+			name:    "flag without component",
+			cfg:     &LoggingConfig{componentLevels: []string{"trace"}},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := tt.cfg.selectComponentLevels()
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLogLevels_String(t *testing.T) {
+	assert.Equal(t, "{}", LogLevels{}.String())
+	assert.Equal(t, "{eventloop: trace, registry: debug}", LogLevels{"registry": "debug", "eventloop": "trace"}.String())
+}
+
+func Test_newLogger_componentLevels(t *testing.T) {
+	cfg := &LoggingConfig{Level: logger.WarnLevel, Levels: LogLevels{"eventloop": logger.TraceLevel, "registry": logger.ErrorLevel}}
+	require.NoError(t, cfg.PostLoad())
+
+	log, err := DefaultLogger(Config{Log: cfg}, nil)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	log.(logger.Controller).SetOutput(buf)
+
+	log.Info("default info")
+	log.Warn("default warn")
+	log.Nested("component", "eventloop").Trace("eventloop trace")
+	log.Nested("component", "registry").Warn("registry warn")
+	log.Nested("component", "registry").Error("registry error")
+	log.Nested("component", "other").Info("other info")
+
+	assert.Equal(t, "[0000]  WARN default warn\n[0000] TRACE eventloop trace component=eventloop\n[0000] ERROR registry error component=registry\n", stripAnsi(buf.String()))
+}
@@ -0,0 +1,58 @@
+package clio
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/anchore/go-logger"
+)
+
+// logRunIDField is the field every log entry is tagged with to correlate entries from a single invocation
+const logRunIDField = "run-id"
+
+// runIDEnvVar returns the environment variable which propagates the run ID to child processes, e.g. <APP>_RUN_ID
+func runIDEnvVar(appName string) string {
+	return envVarName(appName, "RUN_ID")
+}
+
+// newRunID returns the run ID inherited from a parent process via the environment, otherwise a new unique ID
+func newRunID(appName string) string {
+	if id := strings.TrimSpace(os.Getenv(runIDEnvVar(appName))); id != "" {
+		return id
+	}
+	return uuid.NewString()
+}
+
+// runIDLogger tags every log entry with the run ID. The logger from any LoggerConstructor is wrapped, so the run ID does
+// not depend on how the application logger is built.
+type runIDLogger struct {
+	logger.Logger // the wrapped logger, nested with the run ID field
+	wrapped       logger.Logger
+}
+
+var _ interface {
+	logger.Logger
+	logger.Controller
+} = (*runIDLogger)(nil)
+
+func newRunIDLogger(l logger.Logger, runID string) logger.Logger {
+	return &runIDLogger{Logger: l.Nested(logRunIDField, runID), wrapped: l}
+}
+
+// SetOutput sets the output of the wrapped logger, if it can be set
+func (l *runIDLogger) SetOutput(w io.Writer) {
+	if c, ok := l.wrapped.(logger.Controller); ok {
+		c.SetOutput(w)
+	}
+}
+
+// GetOutput returns the output of the wrapped logger, if known
+func (l *runIDLogger) GetOutput() io.Writer {
+	if c, ok := l.wrapped.(logger.Controller); ok {
+		return c.GetOutput()
+	}
+	return nil
+}
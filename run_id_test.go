@@ -0,0 +1,53 @@
+package clio
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+)
+
+func Test_newRunID(t *testing.T) {
+	t.Setenv("MY_APP_RUN_ID", "")
+
+	first := newRunID("my-app")
+	second := newRunID("my-app")
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+
+	t.Setenv("MY_APP_RUN_ID", " parent-run ")
+	assert.Equal(t, "parent-run", newRunID("my-app"))
+}
+
+func Test_runIDEnvVar(t *testing.T) {
+	assert.Equal(t, "MY_APP_RUN_ID", runIDEnvVar("my-app"))
+}
+
+func Test_State_setupLogger_runID(t *testing.T) {
+	buf := &bytes.Buffer{}
+	// a custom logger constructor, which knows nothing of the run ID
+	constructor := SlogLogger(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	s := State{RunID: "abc", Config: Config{Log: &LoggingConfig{Level: logger.InfoLevel}}}
+	require.NoError(t, s.setupLogger(constructor))
+
+	s.Logger.Info("info")
+	s.Logger.Nested("component", "eventloop").Info("nested")
+
+	assert.Equal(t, "level=INFO msg=info run-id=abc\nlevel=INFO msg=nested run-id=abc component=eventloop\n", buf.String())
+
+	// the output of the constructed logger may still be controlled
+	_, ok := s.Logger.(logger.Controller)
+	assert.True(t, ok)
+}
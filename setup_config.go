@@ -1,6 +1,8 @@
 package clio
 
 import (
+	"os"
+
 	"github.com/wagoodman/go-partybus"
 
 	"github.com/anchore/fangs"
@@ -26,6 +28,13 @@ type SetupConfig struct {
 	postConstructs    []postConstruct
 	postRuns          []PostRun
 	mapExitCode       MapExitCode
+	secretReferences  bool
+	interpolation     bool
+	deprecatedKeys    []DeprecatedKey
+	configMigrations  []ConfigMigration
+	strictConfig      strictConfigMode
+	dotEnvFiles       []string
+	slogDefault       bool
 }
 
 func NewSetupConfig(id Identification) *SetupConfig {
@@ -34,14 +43,25 @@ func NewSetupConfig(id Identification) *SetupConfig {
 		LoggerConstructor: DefaultLogger,
 		BusConstructor:    newBus,
 		UIConstructor:     newUI,
-		FangsConfig:       fangs.NewConfig(id.Name).WithConfigEnvVar(),
+		FangsConfig:       withProfileEnvVar(fangs.NewConfig(id.Name).WithConfigEnvVar()),
 		DefaultLoggingConfig: &LoggingConfig{
-			Level: logger.WarnLevel,
+			Level:          logger.WarnLevel,
+			FlightRecorder: defaultFlightRecorderEntries,
 		},
 		// note: no ui selector or dev options by default...
 	}
 }
 
+// withProfileEnvVar looks for the environment variable: <APP_NAME>_PROFILE as a way to select configuration profiles.
+// This will be overridden by the --profile flag
+func withProfileEnvVar(cfg fangs.Config) fangs.Config {
+	profiles := os.Getenv(envVarName(cfg.AppName, "PROFILE"))
+	if profiles != "" && cfg.ProfileKey != "" {
+		cfg.Profiles = fangs.Flatten(profiles)
+	}
+	return cfg
+}
+
 func (c *SetupConfig) WithUI(uis ...UI) *SetupConfig {
 	c.UIConstructor = func(_ Config) (*UICollection, error) {
 		return NewUICollection(uis...), nil
@@ -94,6 +114,71 @@ func (c *SetupConfig) WithNoLogging() *SetupConfig {
 	return c
 }
 
+// WithSecretReferences enables configuration values which reference secrets instead of containing them directly:
+// env:NAME is replaced by the value of the NAME environment variable and file:/path/to/secret is replaced by the
+// contents of the file. Resolved secrets are automatically redacted from logs and configuration output.
+func (c *SetupConfig) WithSecretReferences() *SetupConfig {
+	c.secretReferences = true
+	return c
+}
+
+// WithValueInterpolation enables environment variable references in configuration values: ${NAME} is replaced by the
+// value of the NAME environment variable and ${NAME:-default} uses the default when NAME is unset or empty. Use $${
+// for a literal ${. Interpolated values used within sensitive fields are redacted from logs and configuration output.
+func (c *SetupConfig) WithValueInterpolation() *SetupConfig {
+	c.interpolation = true
+	return c
+}
+
+// WithDeprecatedKeys declares configuration keys which have been renamed. Values set using a deprecated key in
+// configuration files or environment variables are loaded using the replacement key, logging a warning once per key.
+func (c *SetupConfig) WithDeprecatedKeys(keys ...DeprecatedKey) *SetupConfig {
+	c.deprecatedKeys = append(c.deprecatedKeys, keys...)
+	return c
+}
+
+// WithConfigMigrations registers migrations between configuration versions, declared in configuration files using the
+// `config-version` key. Migrations are applied in order: the first migrates version 1 to version 2, the second migrates
+// version 2 to version 3, and so on. Configuration files without a version are version 1. Configuration files are
+// migrated to the latest version before loading.
+func (c *SetupConfig) WithConfigMigrations(migrations ...ConfigMigration) *SetupConfig {
+	c.configMigrations = append(c.configMigrations, migrations...)
+	return c
+}
+
+// WithStrictConfig fails loading configuration when configuration files or <APP_NAME>_ environment variables contain
+// keys which do not map onto a configuration field for any command, suggesting similar keys for likely typos
+func (c *SetupConfig) WithStrictConfig() *SetupConfig {
+	c.strictConfig = strictConfigError
+	return c
+}
+
+// WithStrictConfigWarnings logs a warning when configuration files or <APP_NAME>_ environment variables contain keys
+// which do not map onto a configuration field for any command, suggesting similar keys for likely typos
+func (c *SetupConfig) WithStrictConfigWarnings() *SetupConfig {
+	c.strictConfig = strictConfigWarn
+	return c
+}
+
+// WithDotEnvFiles loads <APP_NAME>_ environment variables from the given dotenv files (default: .env) before loading
+// configuration. Variables already set in the environment take precedence, as do files listed earlier. Missing files
+// are ignored. Note: <APP_NAME>_CONFIG and <APP_NAME>_PROFILE are not read from dotenv files.
+func (c *SetupConfig) WithDotEnvFiles(files ...string) *SetupConfig {
+	if len(files) == 0 {
+		files = []string{defaultDotEnvFile}
+	}
+	c.dotEnvFiles = append(c.dotEnvFiles, files...)
+	return c
+}
+
+// WithSlogDefault installs the application logger as the log/slog default during setup, which also directs output of the
+// standard log package to the application logger, so logs from dependencies are subject to the same redaction, levels,
+// and outputs as the application logs.
+func (c *SetupConfig) WithSlogDefault() *SetupConfig {
+	c.slogDefault = true
+	return c
+}
+
 func (c *SetupConfig) WithInitializers(initializers ...Initializer) *SetupConfig {
 	c.Initializers = append(c.Initializers, initializers...)
 	return c
@@ -110,12 +195,25 @@ func (c *SetupConfig) withPostConstructs(postConstructs ...postConstruct) *Setup
 }
 
 // WithGlobalConfigFlag adds the global `-c` / `--config` flags to the root command
+// The flag may be repeated to merge multiple configuration files, earlier files taking precedence over later files.
+// Configuration files may also reference other files to load with `include` or `extends`, relative to the including
+// file, which take lower precedence than the including file.
 func (c *SetupConfig) WithGlobalConfigFlag() *SetupConfig {
 	return c.withPostConstructs(func(a *application) {
 		a.AddFlags(a.root.PersistentFlags(), &a.setupConfig.FangsConfig)
 	})
 }
 
+// WithGlobalSetFlag adds the global repeatable `--set KEY=VALUE` flag to the root command, which sets any configuration
+// value for all commands (e.g. `--set log.level=debug`), including entries of map fields (e.g.
+// `--set log.levels.eventloop=trace`). Values are checked against the type of the configuration field and take precedence
+// over configuration files and environment variables, but not over dedicated flags for the key.
+func (c *SetupConfig) WithGlobalSetFlag() *SetupConfig {
+	return c.withPostConstructs(func(a *application) {
+		a.root.PersistentFlags().StringArrayVar(&a.configOverrides, configOverrideFlag, nil, "set a configuration value, e.g. --set log.level=debug (may be repeated)")
+	})
+}
+
 // WithGlobalLoggingFlags adds the global logging flags to the root command.
 func (c *SetupConfig) WithGlobalLoggingFlags() *SetupConfig {
 	return c.withPostConstructs(func(a *application) {
@@ -2,6 +2,8 @@ package clio
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
 
 	"github.com/wagoodman/go-partybus"
 
@@ -10,12 +12,19 @@ import (
 )
 
 type State struct {
+	// RunID uniquely identifies this invocation and is attached to every log entry and to events published with
+	// State.Publish, this is inherited by child processes through the <APP>_RUN_ID environment variable so their logs
+	// may be correlated with the parent
+	RunID        string
 	Config       Config
 	Bus          *partybus.Bus
 	Subscription *partybus.Subscription
 	Logger       logger.Logger
 	RedactStore  redact.Store
 	UI           *UICollection
+
+	uiLogs   *uiLogRouter    // routes console log output through the active UI
+	recorder *flightRecorder // keeps recent log entries at every level, if enabled
 }
 
 type Config struct {
@@ -28,18 +37,51 @@ type Config struct {
 }
 
 func (s *State) setup(cfg SetupConfig) error {
+	s.setupRunID(cfg.FangsConfig.AppName)
+
 	s.setupBus(cfg.BusConstructor)
 
+	s.setupRedaction()
+
 	if err := s.setupLogger(cfg.LoggerConstructor); err != nil {
 		return fmt.Errorf("unable to setup logger: %w", err)
 	}
 
+	if s.uiLogs == nil {
+		s.uiLogs = newUILogRouter(os.Stderr)
+	}
+	// console lines are routed through the UI while it owns the terminal
+	s.uiLogs.routeLogger(s.Logger)
+
+	if cfg.slogDefault {
+		// note: this also directs output from the standard log package to the logger
+		slog.SetDefault(slog.New(NewSlogHandler(s.Logger)))
+	}
+
 	if err := s.setupUI(cfg.UIConstructor); err != nil {
 		return fmt.Errorf("unable to setup UI: %w", err)
 	}
 	return nil
 }
 
+// setupRunID selects the run ID once per invocation and exports it for any child processes
+func (s *State) setupRunID(appName string) {
+	if s.RunID == "" {
+		s.RunID = newRunID(appName)
+	}
+	_ = os.Setenv(runIDEnvVar(appName), s.RunID)
+}
+
+// setupRedaction registers the configured redaction patterns with the redact store, so they apply to the logger, the
+// configuration summary, and error messages
+func (s *State) setupRedaction() {
+	store, ok := s.RedactStore.(*redactStore)
+	if !ok || s.Config.Log == nil {
+		return
+	}
+	store.setPatterns(s.Config.Log.redactPatterns)
+}
+
 func (s *State) setupLogger(cx LoggerConstructor) error {
 	if cx == nil {
 		cx = DefaultLogger
@@ -50,6 +92,21 @@ func (s *State) setupLogger(cx LoggerConstructor) error {
 		return err
 	}
 
+	s.recorder = nil
+	if s.Config.Log != nil && s.Config.Log.FlightRecorder > 0 && lgr != nil {
+		// the flight recorder wraps the constructed logger, so it is kept by any logger constructor
+		s.recorder = newFlightRecorder(s.Config.Log.FlightRecorder, logFileFormatter(s.Config.Log.Format))
+		lgr, err = newRecordingLogger(lgr, s.recorder, s.RedactStore)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.RunID != "" && lgr != nil {
+		// note: this wraps the flight recorder, so recorded entries include the run ID
+		lgr = newRunIDLogger(lgr, s.RunID)
+	}
+
 	s.Logger = lgr
 	return nil
 }
@@ -70,5 +127,8 @@ func (s *State) setupUI(cx UIConstructor) error {
 	}
 	var err error
 	s.UI, err = cx(s.Config)
+	if s.UI != nil {
+		s.UI.logs = s.uiLogs
+	}
 	return err
 }
@@ -1,6 +1,7 @@
 package clio
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
@@ -15,6 +16,23 @@ type UI interface {
 	Teardown(force bool) error
 }
 
+// LogHandler is an optional interface for a UI which owns the terminal while active (such as a TUI), where console log
+// output written directly to stderr would corrupt the display. Console log lines are routed for any logger which writes
+// to stderr and implements logger.Controller.
+type LogHandler interface {
+	// HandleLog is called with each formatted console log line while the UI is active, returning false buffers the line
+	// until the UI has been torn down, after which all buffered lines are written to the console in order. This must not
+	// log, as the line is handled while the logger is writing.
+	HandleLog(line []byte) bool
+}
+
+// TerminalOwner is an optional interface for a UI which owns the terminal while active, but does not handle log lines
+// itself (see LogHandler). While such a UI is active, console log lines are buffered until it has been torn down. Log
+// lines are written to the console as usual while any other UI is active.
+type TerminalOwner interface {
+	OwnsTerminal() bool
+}
+
 var _ UIConstructor = newUI
 
 func newUI(Config) (*UICollection, error) {
@@ -29,6 +47,7 @@ type UICollection struct {
 	active       UI
 	subscription partybus.Unsubscribable
 	lock         *sync.Mutex
+	logs         *uiLogRouter // routes console log output to the active UI, set by the application
 }
 
 func NewUICollection(uis ...UI) *UICollection {
@@ -55,6 +74,7 @@ func (u *UICollection) setup(subscription partybus.Unsubscribable) error {
 		setupErr = nil
 
 		u.active = ui
+		u.logs.activate(ui)
 		break
 	}
 	return setupErr
@@ -79,9 +99,11 @@ func (u *UICollection) teardown(force bool) error {
 	if u.active == nil {
 		return nil
 	}
+	// log lines are held while the UI is torn down, since it may still own the terminal
+	u.logs.hold()
 	err := u.active.Teardown(force)
 	u.active = nil
-	return err
+	return errors.Join(err, u.logs.flush())
 }
 
 func (u *UICollection) Replace(uis ...UI) error {
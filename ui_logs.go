@@ -0,0 +1,132 @@
+package clio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/anchore/go-logger"
+)
+
+// maxBufferedUILogLines is the number of console log lines buffered while a UI owns the terminal, the oldest lines are
+// dropped beyond this
+const maxBufferedUILogLines = 1000
+
+// uiLogRouter routes console log lines to the active UI when it implements LogHandler, buffering lines the UI does not
+// handle, or all lines when the UI owns the terminal (see TerminalOwner), until the UI has been torn down. Otherwise,
+// lines are written to the console.
+type uiLogRouter struct {
+	lock    sync.Mutex
+	out     io.Writer
+	handler LogHandler // the active UI, if it handles logs
+	holding bool       // buffer all lines, such as while the UI owns the terminal or is torn down
+	lines   [][]byte
+	dropped int // the number of lines dropped from the buffer
+}
+
+var _ io.Writer = (*uiLogRouter)(nil)
+
+func newUILogRouter(out io.Writer) *uiLogRouter {
+	return &uiLogRouter{out: out}
+}
+
+// routeLogger directs the console output of the logger through the router. Only loggers which allow their output to
+// be set, and write to stderr, are routed, so this applies to the logger from any LoggerConstructor.
+func (r *uiLogRouter) routeLogger(l logger.Logger) {
+	if r == nil {
+		return
+	}
+	c, ok := l.(logger.Controller)
+	if !ok || c.GetOutput() != os.Stderr {
+		return
+	}
+	c.SetOutput(r)
+}
+
+// activate routes lines to the UI if it handles logs, or buffers all lines until the UI is torn down if it owns the
+// terminal, otherwise lines are written to the console while the UI is active
+func (r *uiLogRouter) activate(ui UI) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if handler, ok := ui.(LogHandler); ok {
+		r.handler = handler
+		return
+	}
+	if owner, ok := ui.(TerminalOwner); ok && owner.OwnsTerminal() {
+		r.holding = true
+	}
+}
+
+// hold buffers all lines which would otherwise be routed to the UI
+func (r *uiLogRouter) hold() {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.handler != nil {
+		r.handler = nil
+		r.holding = true
+	}
+}
+
+// flush writes all buffered lines in order, after which lines are written to the console
+func (r *uiLogRouter) flush() error {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.holding = false
+	lines := r.lines
+	r.lines = nil
+	if r.dropped > 0 {
+		lines = append([][]byte{fmt.Appendf(nil, "[%d earlier log lines were dropped while the UI was active]\n", r.dropped)}, lines...)
+		r.dropped = 0
+	}
+	for _, line := range lines {
+		if _, err := r.out.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write routes the line to the UI, buffers it, or writes it to the console
+func (r *uiLogRouter) Write(line []byte) (int, error) {
+	if len(line) == 0 {
+		// entries filtered from the console (see levelFilterFormatter) are still written, but are empty
+		return 0, nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.handler == nil && !r.holding {
+		return r.out.Write(line)
+	}
+
+	// the caller may reuse the line
+	buffered := bytes.Clone(line)
+	if r.handler == nil || !r.handler.HandleLog(buffered) {
+		r.buffer(buffered)
+	}
+	return len(line), nil
+}
+
+// buffer keeps the line until the UI has been torn down, dropping the oldest line when the buffer is full
+func (r *uiLogRouter) buffer(line []byte) {
+	if len(r.lines) >= maxBufferedUILogLines {
+		r.lines = r.lines[1:]
+		r.dropped++
+	}
+	r.lines = append(r.lines, line)
+}
@@ -0,0 +1,237 @@
+package clio
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/logrus"
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+type logHandlerUI struct {
+	uiMocker
+	handle  bool
+	handled []string
+}
+
+func (u *logHandlerUI) HandleLog(line []byte) bool {
+	if u.handle {
+		u.handled = append(u.handled, string(line))
+	}
+	return u.handle
+}
+
+type terminalOwnerUI struct {
+	uiMocker
+}
+
+func (u *terminalOwnerUI) OwnsTerminal() bool {
+	return true
+}
+
+func Test_uiLogRouter(t *testing.T) {
+	tests := []struct {
+		name        string
+		ui          UI
+		wantHandled []string
+		wantActive  string
+		wantOut     string
+	}{
+		{
+			name:       "UI without log handler",
+			ui:         &uiMocker{},
+			wantActive: "before\nfirst\nsecond\n",
+			wantOut:    "before\nfirst\nsecond\nduring teardown\nafter\n",
+		},
+		{
+			name:    "UI owns the terminal",
+			ui:      &terminalOwnerUI{},
+			wantOut: "before\nfirst\nsecond\nduring teardown\nafter\n",
+		},
+		{
+			name:        "UI handles logs",
+			ui:          &logHandlerUI{handle: true},
+			wantHandled: []string{"first\n", "second\n"},
+			wantOut:     "before\nduring teardown\nafter\n",
+		},
+		{
+			name:    "UI buffers logs",
+			ui:      &logHandlerUI{},
+			wantOut: "before\nfirst\nsecond\nduring teardown\nafter\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			router := newUILogRouter(out)
+
+			write := func(line string) {
+				n, err := router.Write([]byte(line))
+				require.NoError(t, err)
+				require.Equal(t, len(line), n)
+			}
+
+			var mocker *uiMocker
+			switch ui := tt.ui.(type) {
+			case *uiMocker:
+				mocker = ui
+			case *logHandlerUI:
+				mocker = &ui.uiMocker
+			case *terminalOwnerUI:
+				mocker = &ui.uiMocker
+			}
+			mocker.On("Setup", nil).Return(nil)
+			mocker.On("Teardown", false).Run(func(_ mock.Arguments) {
+				write("during teardown\n")
+			}).Return(nil)
+
+			c := NewUICollection(tt.ui)
+			c.logs = router
+
+			write("before\n")
+			require.NoError(t, c.Setup(nil))
+			write("first\n")
+			write("second\n")
+			if tt.wantActive == "" {
+				tt.wantActive = "before\n"
+			}
+			assert.Equal(t, tt.wantActive, out.String())
+
+			require.NoError(t, c.Teardown(false))
+			write("after\n")
+
+			assert.Equal(t, tt.wantOut, out.String())
+			if ui, ok := tt.ui.(*logHandlerUI); ok {
+				assert.Equal(t, tt.wantHandled, ui.handled)
+			}
+		})
+	}
+}
+
+func Test_uiLogRouter_bufferLimit(t *testing.T) {
+	out := &bytes.Buffer{}
+	router := newUILogRouter(out)
+	router.activate(&terminalOwnerUI{})
+
+	for i := 0; i < maxBufferedUILogLines+2; i++ {
+		_, err := fmt.Fprintf(router, "line %d\n", i)
+		require.NoError(t, err)
+	}
+	assert.Empty(t, out.String())
+	assert.Len(t, router.lines, maxBufferedUILogLines)
+
+	require.NoError(t, router.flush())
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, maxBufferedUILogLines+1)
+	assert.Equal(t, "[2 earlier log lines were dropped while the UI was active]", lines[0])
+	assert.Equal(t, "line 2", lines[1])
+	assert.Equal(t, fmt.Sprintf("line %d", maxBufferedUILogLines+1), lines[len(lines)-1])
+}
+
+func Test_State_uiLogs(t *testing.T) {
+	tests := []struct {
+		name        string
+		constructor LoggerConstructor
+	}{
+		{
+			name:        "default logger",
+			constructor: DefaultLogger,
+		},
+		{
+			name: "custom logger",
+			constructor: func(_ Config, _ redact.Store) (logger.Logger, error) {
+				return logrus.New(logrus.Config{EnableConsole: true, Level: logger.InfoLevel})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the run ID is exported while setting up, which is restored after the test
+			t.Setenv(runIDEnvVar(""), "")
+
+			buf := &bytes.Buffer{}
+			ui := &logHandlerUI{handle: true}
+			ui.On("Setup", nil).Return(nil)
+			ui.On("Teardown", false).Return(nil)
+
+			s := State{
+				Config: Config{Log: &LoggingConfig{Level: logger.InfoLevel}},
+				uiLogs: newUILogRouter(buf),
+			}
+			require.NoError(t, s.setup(SetupConfig{
+				LoggerConstructor: tt.constructor,
+				UIConstructor: func(Config) (*UICollection, error) {
+					return NewUICollection(ui), nil
+				},
+			}))
+
+			s.Logger.Info("before")
+			require.NoError(t, s.UI.Setup(nil))
+			s.Logger.Info("while the UI is active")
+			s.Logger.Debug("not logged")
+			require.NoError(t, s.UI.Teardown(false))
+			s.Logger.Info("after")
+
+			assert.Contains(t, stripAnsi(buf.String()), "before")
+			assert.Contains(t, stripAnsi(buf.String()), "after")
+			assert.NotContains(t, buf.String(), "while the UI is active")
+			require.Len(t, ui.handled, 1)
+			assert.Contains(t, ui.handled[0], "while the UI is active")
+		})
+	}
+}
+
+func Test_State_uiLogs_filteredEntries(t *testing.T) {
+	t.Setenv(runIDEnvVar(""), "")
+
+	ui := &logHandlerUI{handle: true}
+	ui.On("Setup", nil).Return(nil)
+	ui.On("Teardown", false).Return(nil)
+
+	// the log file is more verbose than the console, so entries filtered from the console are still written to it
+	s := State{
+		Config: Config{Log: &LoggingConfig{
+			Level:        logger.WarnLevel,
+			FileLevel:    logger.DebugLevel,
+			FileLocation: filepath.Join(t.TempDir(), "app.log"),
+		}},
+		uiLogs: newUILogRouter(&bytes.Buffer{}),
+	}
+	require.NoError(t, s.setup(SetupConfig{
+		LoggerConstructor: DefaultLogger,
+		UIConstructor: func(Config) (*UICollection, error) {
+			return NewUICollection(ui), nil
+		},
+	}))
+
+	require.NoError(t, s.UI.Setup(nil))
+	s.Logger.Debug("file only")
+	s.Logger.Warn("everywhere")
+	require.NoError(t, s.UI.Teardown(false))
+
+	require.Len(t, ui.handled, 1)
+	assert.Contains(t, ui.handled[0], "everywhere")
+}
+
+func Test_State_uiLogs_quiet(t *testing.T) {
+	t.Setenv(runIDEnvVar(""), "")
+
+	buf := &bytes.Buffer{}
+	s := State{
+		Config: Config{Log: &LoggingConfig{Level: logger.InfoLevel, Quiet: true}},
+		uiLogs: newUILogRouter(buf),
+	}
+	require.NoError(t, s.setup(SetupConfig{LoggerConstructor: DefaultLogger}))
+
+	// the console is disabled, so is never routed
+	s.Logger.Info("quiet")
+	assert.Empty(t, buf.String())
+}